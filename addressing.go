@@ -0,0 +1,155 @@
+package soap
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WS-Addressing namespaces.
+const (
+	// AddressingNamespace is the WS-Addressing 1.0 namespace (2005/08).
+	AddressingNamespace = "http://www.w3.org/2005/08/addressing"
+	// AddressingNamespace200408 is the WS-Addressing member submission namespace (2004/08).
+	AddressingNamespace200408 = "http://schemas.xmlsoap.org/ws/2004/08/addressing"
+)
+
+// ErrRelatesToMismatch is returned when the wsa:RelatesTo header of a response
+// does not match the wsa:MessageID of the request.
+var ErrRelatesToMismatch = errors.New("wsa:RelatesTo does not match request wsa:MessageID")
+
+// Addressing configures the WS-Addressing headers added by [WithAddressing].
+type Addressing struct {
+	// Namespace is the WS-Addressing namespace, either [AddressingNamespace]
+	// or [AddressingNamespace200408]. Defaults to [AddressingNamespace].
+	Namespace string
+
+	// To overrides the destination address. Defaults to the client endpoint.
+	To string
+
+	// ReplyTo is the address replies are sent to. Defaults to the anonymous
+	// address, meaning the reply is returned in the HTTP response.
+	ReplyTo string
+
+	// FaultTo is the address faults are sent to. Omitted when empty.
+	FaultTo string
+}
+
+// WithAddressing adds WS-Addressing headers to every request.
+//
+// wsa:Action is set from the action passed to [Client.Call], wsa:To from the
+// endpoint and wsa:MessageID to a new random UUID URN. Successful responses
+// must carry a wsa:RelatesTo header referencing the request's MessageID,
+// otherwise the call fails with [ErrRelatesToMismatch].
+func WithAddressing(addressing Addressing) ClientOption {
+	return func(c *clientConfig) {
+		c.addressing = &addressing
+	}
+}
+
+// namespace returns the configured WS-Addressing namespace.
+func (a *Addressing) namespace() string {
+	if a.Namespace == "" {
+		return AddressingNamespace
+	}
+	return a.Namespace
+}
+
+// anonymousAddress returns the anonymous endpoint address of the namespace.
+func (a *Addressing) anonymousAddress() string {
+	if a.namespace() == AddressingNamespace200408 {
+		return AddressingNamespace200408 + "/role/anonymous"
+	}
+	return AddressingNamespace + "/anonymous"
+}
+
+// applyAddressing adds the WS-Addressing headers to the envelope and returns
+// the generated message ID.
+func applyAddressing(env *Envelope, action, endpoint string, addressing *Addressing) (string, error) {
+	if action == "" {
+		return "", fmt.Errorf("WS-Addressing requires an action")
+	}
+	to := addressing.To
+	if to == "" {
+		to = endpoint
+	}
+	replyTo := addressing.ReplyTo
+	if replyTo == "" {
+		replyTo = addressing.anonymousAddress()
+	}
+	messageID, err := newMessageID()
+	if err != nil {
+		return "", err
+	}
+	ns := addressing.namespace()
+	actionEntry := addressingHeaderEntry(ns, "Action", escapeText(action))
+	actionEntry.Attrs = append(actionEntry.Attrs, xml.Attr{Name: env.elementName("mustUnderstand"), Value: "1"})
+	env.addHeaderEntry(actionEntry)
+	env.addHeaderEntry(addressingHeaderEntry(ns, "To", escapeText(to)))
+	env.addHeaderEntry(addressingHeaderEntry(ns, "MessageID", escapeText(messageID)))
+	env.addHeaderEntry(addressingHeaderEntry(ns, "ReplyTo", endpointReference(replyTo)))
+	if addressing.FaultTo != "" {
+		env.addHeaderEntry(addressingHeaderEntry(ns, "FaultTo", endpointReference(addressing.FaultTo)))
+	}
+	return messageID, nil
+}
+
+// addressingHeaderEntry builds a wsa header entry with the given XML content.
+func addressingHeaderEntry(namespace, local, content string) HeaderEntry {
+	return HeaderEntry{
+		XMLName: xml.Name{Local: "wsa:" + local},
+		Content: []byte(content),
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "xmlns:wsa"}, Value: namespace}},
+	}
+}
+
+// endpointReference returns the content of an endpoint reference with the given address.
+func endpointReference(address string) string {
+	return "<wsa:Address>" + escapeText(address) + "</wsa:Address>"
+}
+
+// escapeText escapes a string for use as XML character data.
+func escapeText(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// newMessageID generates a random (version 4) UUID URN.
+func newMessageID() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
+
+// checkRelatesTo verifies that the response relates to the request's message ID.
+func checkRelatesTo(response *Envelope, messageID string, addressing *Addressing) error {
+	var relatesTo string
+	if response.Header != nil {
+		for _, entry := range response.Header.Entries {
+			if entry.XMLName.Space != addressing.namespace() || entry.XMLName.Local != "RelatesTo" {
+				continue
+			}
+			var text struct {
+				Value string `xml:",chardata"`
+			}
+			wrapped := append(append([]byte("<RelatesTo>"), entry.Content...), "</RelatesTo>"...)
+			if err := xml.Unmarshal(wrapped, &text); err != nil {
+				return fmt.Errorf("invalid wsa:RelatesTo header: %w", err)
+			}
+			relatesTo = strings.TrimSpace(text.Value)
+			break
+		}
+	}
+	if relatesTo != messageID {
+		return fmt.Errorf("%w: expected %q, got %q", ErrRelatesToMismatch, messageID, relatesTo)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// addressingHeaders are the decoded WS-Addressing headers of a request.
+type addressingHeaders struct {
+	Action struct {
+		XMLName        xml.Name
+		MustUnderstand string `xml:"http://schemas.xmlsoap.org/soap/envelope/ mustUnderstand,attr"`
+		Value          string `xml:",chardata"`
+	} `xml:"Header>Action"`
+	To        string `xml:"Header>To"`
+	MessageID string `xml:"Header>MessageID"`
+	ReplyTo   string `xml:"Header>ReplyTo>Address"`
+	FaultTo   string `xml:"Header>FaultTo>Address"`
+}
+
+// addressingServer returns a test server that decodes the WS-Addressing headers
+// of the request and responds with the given wsa:RelatesTo value, or the
+// request's MessageID when relatesTo is nil.
+func addressingServer(t *testing.T, namespace string, relatesTo *string, headers *addressingHeaders) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, headers); err != nil {
+			t.Fatalf("Failed to unmarshal request: %v", err)
+		}
+		if headers.Action.XMLName.Space != namespace {
+			t.Errorf("Expected addressing namespace %q, got %q", namespace, headers.Action.XMLName.Space)
+		}
+		value := headers.MessageID
+		if relatesTo != nil {
+			value = *relatesTo
+		}
+		respEnv, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+		respEnv.Header = &Header{
+			XMLName: xml.Name{Local: "soapenv:Header"},
+			Entries: []HeaderEntry{addressingHeaderEntry(namespace, "RelatesTo", escapeText(value))},
+		}
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+}
+
+func TestClient_Addressing(t *testing.T) {
+	t.Parallel()
+	var headers addressingHeaders
+	server := addressingServer(t, AddressingNamespace, nil, &headers)
+	defer server.Close()
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithMaxRetries(0),
+		WithAddressing(Addressing{FaultTo: "http://example.com/faults"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	if _, err := client.Call(context.Background(), "urn:example:Order", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if headers.Action.Value != "urn:example:Order" || headers.Action.MustUnderstand != "1" {
+		t.Errorf("Unexpected wsa:Action: %+v", headers.Action)
+	}
+	if headers.To != server.URL {
+		t.Errorf("Expected wsa:To %q, got %q", server.URL, headers.To)
+	}
+	if !strings.HasPrefix(headers.MessageID, "urn:uuid:") || len(headers.MessageID) != len("urn:uuid:")+36 {
+		t.Errorf("Expected a UUID URN message ID, got %q", headers.MessageID)
+	}
+	if headers.ReplyTo != "http://www.w3.org/2005/08/addressing/anonymous" {
+		t.Errorf("Expected anonymous wsa:ReplyTo, got %q", headers.ReplyTo)
+	}
+	if headers.FaultTo != "http://example.com/faults" {
+		t.Errorf("Unexpected wsa:FaultTo: %q", headers.FaultTo)
+	}
+	if reqEnv.Header != nil {
+		t.Error("Expected the caller's envelope to be left unmodified")
+	}
+}
+
+func TestClient_Addressing200408(t *testing.T) {
+	t.Parallel()
+	var headers addressingHeaders
+	server := addressingServer(t, AddressingNamespace200408, nil, &headers)
+	defer server.Close()
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithMaxRetries(0),
+		WithAddressing(Addressing{Namespace: AddressingNamespace200408, To: "urn:service", ReplyTo: "urn:reply"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	if _, err := client.Call(context.Background(), "urn:example:Order", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if headers.To != "urn:service" || headers.ReplyTo != "urn:reply" {
+		t.Errorf("Unexpected addressing headers: %+v", headers)
+	}
+}
+
+func TestClient_AddressingRelatesToMismatch(t *testing.T) {
+	t.Parallel()
+	var headers addressingHeaders
+	other := "urn:uuid:00000000-0000-4000-8000-000000000000"
+	server := addressingServer(t, AddressingNamespace, &other, &headers)
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithAddressing(Addressing{}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	_, err = client.Call(context.Background(), "urn:example:Order", reqEnv)
+	if !errors.Is(err, ErrRelatesToMismatch) {
+		t.Fatalf("Expected ErrRelatesToMismatch, got: %v", err)
+	}
+}
+
+func TestClient_AddressingRequiresAction(t *testing.T) {
+	t.Parallel()
+	client, err := NewClient(WithEndpoint("http://localhost"), WithAddressing(Addressing{}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	if _, err := client.Call(context.Background(), "", reqEnv); err == nil {
+		t.Fatal("Expected an error for a call without action")
+	}
+}
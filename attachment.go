@@ -0,0 +1,75 @@
+package soap
+
+import (
+	"bytes"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Attachment is a MIME part carried alongside a SOAP envelope in a
+// multipart/related message, as described by SOAP Messages with Attachments (SwA).
+type Attachment struct {
+	// ContentID identifies the part, without angle brackets.
+	// When empty, a Content-ID is generated for outgoing attachments.
+	ContentID string
+
+	// ContentType is the media type of the part.
+	// Defaults to application/octet-stream for outgoing attachments.
+	ContentType string
+
+	// Data is the raw content of the part.
+	Data []byte
+}
+
+// Reader returns an [io.Reader] reading the attachment data.
+func (a Attachment) Reader() io.Reader {
+	return bytes.NewReader(a.Data)
+}
+
+// FindAttachment returns the attachment with the given Content-ID.
+// Angle brackets and a cid: prefix are ignored, so references taken
+// directly from the envelope can be used.
+func FindAttachment(attachments []Attachment, contentID string) (Attachment, bool) {
+	contentID = normalizeContentID(contentID)
+	for _, attachment := range attachments {
+		if normalizeContentID(attachment.ContentID) == contentID {
+			return attachment, true
+		}
+	}
+	return Attachment{}, false
+}
+
+// attachmentPart converts an attachment to a MIME part.
+func attachmentPart(attachment Attachment, index int) *mimePart {
+	contentID := normalizeContentID(attachment.ContentID)
+	if contentID == "" {
+		contentID = "attachment" + strconv.Itoa(index+1) + "@soap-go"
+	}
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	part := &mimePart{header: textproto.MIMEHeader{}, data: attachment.Data}
+	part.header.Set("Content-Type", contentType)
+	part.header.Set("Content-Transfer-Encoding", "binary")
+	part.header.Set("Content-ID", "<"+contentID+">")
+	return part
+}
+
+// partAttachment converts a received MIME part to an attachment.
+func partAttachment(part *mimePart) Attachment {
+	return Attachment{
+		ContentID:   part.contentID(),
+		ContentType: part.header.Get("Content-Type"),
+		Data:        part.data,
+	}
+}
+
+// normalizeContentID strips a cid: prefix and angle brackets from a Content-ID.
+func normalizeContentID(contentID string) string {
+	contentID = strings.TrimSpace(contentID)
+	contentID = strings.TrimPrefix(contentID, "cid:")
+	return strings.Trim(contentID, "<>")
+}
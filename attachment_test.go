@@ -0,0 +1,116 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_AttachmentsRequest(t *testing.T) {
+	t.Parallel()
+	photo := []byte("\xff\xd8\xff\xe0 jpeg data")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if !strings.HasPrefix(contentType, "multipart/related;") || !strings.Contains(contentType, `type="text/xml"`) {
+			t.Errorf("Expected SwA content type, got: %s", contentType)
+		}
+		body, _ := io.ReadAll(r.Body)
+		root, parts, err := decodeMultipartRelated(contentType, body)
+		if err != nil {
+			t.Fatalf("Failed to decode multipart request: %v", err)
+		}
+		if got := root.header.Get("Content-Type"); got != "text/xml; charset=utf-8" {
+			t.Errorf("Expected root part content type 'text/xml; charset=utf-8', got: %s", got)
+		}
+		if !strings.Contains(string(root.data), "<SubmitClaim>") {
+			t.Errorf("Expected envelope in root part: %s", root.data)
+		}
+		if len(parts) != 2 {
+			t.Fatalf("Expected 2 attachment parts, got %d", len(parts))
+		}
+		if parts[0].contentID() != "photo@example.com" || parts[0].header.Get("Content-Type") != "image/jpeg" {
+			t.Errorf("Unexpected photo part header: %v", parts[0].header)
+		}
+		if !bytes.Equal(parts[0].data, photo) {
+			t.Error("Photo attachment content does not match")
+		}
+		if parts[1].contentID() != "attachment2@soap-go" ||
+			parts[1].header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Expected generated defaults for the second part, got: %v", parts[1].header)
+		}
+		respEnv, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, err := NewEnvelope(WithBody([]byte(`<SubmitClaim></SubmitClaim>`)))
+	if err != nil {
+		t.Fatalf("Failed to create envelope: %v", err)
+	}
+	reqEnv.Attachments = []Attachment{
+		{ContentID: "<photo@example.com>", ContentType: "image/jpeg", Data: photo},
+		{Data: []byte("notes")},
+	}
+	respEnv, err := client.Call(context.Background(), "urn:SubmitClaim", reqEnv)
+	if err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if len(respEnv.Attachments) != 0 {
+		t.Errorf("Expected no response attachments, got %d", len(respEnv.Attachments))
+	}
+}
+
+func TestClient_AttachmentsResponse(t *testing.T) {
+	t.Parallel()
+	doc := testDocument()
+	form := []byte("%PDF-1.7 form")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respEnv, _ := NewEnvelope(WithBody(doc))
+		respXML, _ := xml.Marshal(respEnv)
+		body, contentType, err := encodeMultipartRelated(respXML, Version11, "", true, []string{"Document/Content"}, []Attachment{
+			{ContentID: "form@example.com", ContentType: "application/pdf", Data: form},
+		})
+		if err != nil {
+			t.Fatalf("Failed to encode multipart response: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<GetClaimForm/>`)))
+	respEnv, err := client.Call(context.Background(), "", reqEnv)
+	if err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	var result documentPayload
+	if err := xml.Unmarshal(respEnv.Body.Content, &result); err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+	if !bytes.Equal(result.Content, doc.Content) {
+		t.Error("Expected XOP include to be resolved into the document content")
+	}
+	if len(respEnv.Attachments) != 1 {
+		t.Fatalf("Expected only the unreferenced part as attachment, got %d", len(respEnv.Attachments))
+	}
+	attachment, ok := FindAttachment(respEnv.Attachments, "cid:form@example.com")
+	if !ok {
+		t.Fatal("Expected to find the form attachment")
+	}
+	if attachment.ContentType != "application/pdf" || !bytes.Equal(attachment.Data, form) {
+		t.Errorf("Unexpected attachment: %+v", attachment)
+	}
+}
@@ -0,0 +1,302 @@
+package soap
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// challengeAuth is an HTTP authentication scheme answering 401 challenges.
+type challengeAuth interface {
+	// roundTrip sends a request, authenticating it with as many requests to
+	// next as the scheme needs. The request body can be read again by calling
+	// GetBody.
+	roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error)
+}
+
+// authTransport authenticates attempts with a challenge-response scheme.
+type authTransport struct {
+	auth challengeAuth
+	next http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// Buffer the body, so that it can be sent on every leg of the handshake
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+	return t.auth.roundTrip(t.next, req)
+}
+
+// authLeg returns a copy of a request with a fresh body, for a leg of an
+// authentication handshake.
+func authLeg(req *http.Request, first bool) (*http.Request, error) {
+	leg := req.Clone(req.Context())
+	if !first && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reset request body: %w", err)
+		}
+		leg.Body = body
+	}
+	return leg, nil
+}
+
+// discardResponse drains and closes the body of an intermediate response of a
+// handshake, so that the connection is reused by the next leg.
+func discardResponse(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// authChallenges returns the parameters of the challenges of a scheme in the
+// WWW-Authenticate headers of a 401 response.
+func authChallenges(resp *http.Response, scheme string) []string {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return nil
+	}
+	var challenges []string
+	for _, value := range resp.Header.Values("WWW-Authenticate") {
+		name, params, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if strings.EqualFold(name, scheme) {
+			challenges = append(challenges, strings.TrimSpace(params))
+		}
+	}
+	return challenges
+}
+
+// WithDigestAuth authenticates requests with HTTP Digest authentication
+// (RFC 7616), with the MD5 and SHA-256 algorithms and the auth and auth-int
+// qualities of protection.
+//
+// The first request answers the challenge of the server, and the following
+// requests of the client reuse it until the server issues a new nonce.
+func WithDigestAuth(username, password string) ClientOption {
+	auth := &digestAuth{username: username, password: password}
+	return func(c *clientConfig) {
+		c.auth = auth
+	}
+}
+
+// digestAuth is the state of HTTP Digest authentication, shared by the calls
+// of a client.
+type digestAuth struct {
+	username  string
+	password  string
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nc        int
+}
+
+// digestChallenge is a parsed Digest challenge.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	userhash  bool
+}
+
+func (a *digestAuth) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	for i := 0; ; i++ {
+		leg, err := authLeg(req, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		if a.challenge != nil {
+			a.nc++
+			authorization, err := a.authorization(leg, a.challenge, a.nc)
+			if err != nil {
+				a.mu.Unlock()
+				return nil, err
+			}
+			leg.Header.Set("Authorization", authorization)
+		}
+		answered := a.challenge
+		a.mu.Unlock()
+		resp, err := next.RoundTrip(leg)
+		if err != nil || i > 0 {
+			return resp, err
+		}
+		challenge := parseDigestChallenge(authChallenges(resp, "Digest"))
+		if challenge == nil {
+			return resp, nil
+		}
+		if answered != nil && challenge.nonce == answered.nonce {
+			// The server rejected the credentials, not a stale nonce
+			return resp, nil
+		}
+		discardResponse(resp)
+		a.mu.Lock()
+		a.challenge = challenge
+		a.nc = 0
+		a.mu.Unlock()
+	}
+}
+
+// parseDigestChallenge returns the first Digest challenge with a supported
+// algorithm and quality of protection, or nil if there is none.
+func parseDigestChallenge(challenges []string) *digestChallenge {
+	for _, challenge := range challenges {
+		params := parseAuthParams(challenge)
+		c := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			userhash:  strings.EqualFold(params["userhash"], "true"),
+		}
+		if c.algorithm == "" {
+			c.algorithm = "MD5"
+		}
+		if digestAlgorithmHash(c.algorithm) == nil || c.nonce == "" {
+			continue
+		}
+		if qop, ok := params["qop"]; ok {
+			for option := range strings.SplitSeq(qop, ",") {
+				option = strings.TrimSpace(option)
+				if option == "auth" || (option == "auth-int" && c.qop == "") {
+					c.qop = option
+				}
+			}
+			if c.qop == "" {
+				continue
+			}
+		}
+		return c
+	}
+	return nil
+}
+
+// parseAuthParams parses the comma-separated name=value parameters of a
+// challenge, whose values may be quoted strings.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s != "" {
+		s = strings.TrimLeft(s, ", \t")
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		rest = strings.TrimLeft(rest, " \t")
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				value.WriteByte(rest[i])
+			}
+			s = rest[min(i+1, len(rest)):]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(strings.TrimSpace(rest[:end]))
+			s = rest[end:]
+		}
+		params[name] = value.String()
+	}
+	return params
+}
+
+// digestAlgorithmHash returns the hash function of a Digest algorithm, or nil
+// if the algorithm is not supported.
+func digestAlgorithmHash(algorithm string) func() hash.Hash {
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "MD5":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	}
+	return nil
+}
+
+// authorization returns the Authorization header answering a challenge.
+func (a *digestAuth) authorization(req *http.Request, c *digestChallenge, nc int) (string, error) {
+	newHash := digestAlgorithmHash(c.algorithm)
+	h := func(parts ...string) string {
+		hash := newHash()
+		_, _ = io.WriteString(hash, strings.Join(parts, ":"))
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+	var cnonceBytes [16]byte
+	_, _ = rand.Read(cnonceBytes[:])
+	cnonce := hex.EncodeToString(cnonceBytes[:])
+	ncValue := fmt.Sprintf("%08x", nc)
+	uri := req.URL.RequestURI()
+
+	ha1 := h(a.username, c.realm, a.password)
+	if strings.HasSuffix(strings.ToLower(c.algorithm), "-sess") {
+		ha1 = h(ha1, c.nonce, cnonce)
+	}
+	ha2 := h(req.Method, uri)
+	if c.qop == "auth-int" {
+		var body []byte
+		if req.GetBody != nil {
+			r, err := req.GetBody()
+			if err != nil {
+				return "", fmt.Errorf("failed to read request body: %w", err)
+			}
+			body, err = io.ReadAll(r)
+			if err != nil {
+				return "", fmt.Errorf("failed to read request body: %w", err)
+			}
+		}
+		bodyHash := newHash()
+		_, _ = bodyHash.Write(body)
+		ha2 = h(req.Method, uri, hex.EncodeToString(bodyHash.Sum(nil)))
+	}
+	var response string
+	if c.qop == "" {
+		response = h(ha1, c.nonce, ha2)
+	} else {
+		response = h(ha1, c.nonce, ncValue, cnonce, c.qop, ha2)
+	}
+
+	username := a.username
+	if c.userhash {
+		username = h(a.username, c.realm)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username=%s, realm=%s, nonce=%s, uri=%s, algorithm=%s, response="%s"`,
+		quoteAuthParam(username), quoteAuthParam(c.realm), quoteAuthParam(c.nonce), quoteAuthParam(uri), c.algorithm, response)
+	if c.opaque != "" {
+		fmt.Fprintf(&b, ", opaque=%s", quoteAuthParam(c.opaque))
+	}
+	if c.qop != "" {
+		fmt.Fprintf(&b, `, qop=%s, nc=%s, cnonce="%s"`, c.qop, ncValue, cnonce)
+	}
+	if c.userhash {
+		b.WriteString(", userhash=true")
+	}
+	return b.String(), nil
+}
+
+// quoteAuthParam returns a quoted string for an authentication parameter.
+func quoteAuthParam(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,157 @@
+package soap
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newEchoHandler returns a SOAP server echoing the message of urn:echo requests.
+func newEchoHandler() http.Handler {
+	server := NewServer()
+	Handle(server, "urn:echo", func(ctx context.Context, req *echoRequest) (*echoResponse, error) {
+		return &echoResponse{Message: req.Message}, nil
+	})
+	return server
+}
+
+// newDigestServer starts a test server requiring Digest authentication, which
+// issues a new nonce after nonceUses requests. It counts the challenges.
+func newDigestServer(t *testing.T, algorithm, qop, password string, nonceUses int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var challenges atomic.Int32
+	var mu sync.Mutex
+	var nonce string
+	var uses int
+	newHash := map[string]func() hash.Hash{"MD5": md5.New, "SHA-256": sha256.New}[algorithm]
+	h := func(parts ...string) string {
+		hash := newHash()
+		_, _ = io.WriteString(hash, strings.Join(parts, ":"))
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+	echo := newEchoHandler()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		params := parseAuthParams(credentials)
+		stale := false
+		if scheme == "Digest" && nonce != "" {
+			bodyHash := newHash()
+			bodyHash.Write(body)
+			ha2 := h(r.Method, params["uri"])
+			if qop == "auth-int" {
+				ha2 = h(r.Method, params["uri"], hex.EncodeToString(bodyHash.Sum(nil)))
+			}
+			expected := h(h("user", "test", password), params["nonce"], params["nc"], params["cnonce"], qop, ha2)
+			switch {
+			case params["response"] != expected || params["opaque"] != "opaque-value" || params["uri"] != r.URL.RequestURI():
+			case params["nonce"] != nonce:
+				stale = true
+			default:
+				uses++
+				if uses == nonceUses {
+					nonce = ""
+				}
+				r.Body = io.NopCloser(strings.NewReader(string(body)))
+				echo.ServeHTTP(w, r)
+				return
+			}
+		}
+		if nonce == "" {
+			nonce = fmt.Sprintf("nonce-%d", challenges.Load())
+			uses = 0
+		}
+		challenges.Add(1)
+		w.Header().Set("WWW-Authenticate", "Basic realm=\"test\"")
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(
+			`Digest realm="test", qop="%s", algorithm=%s, nonce="%s", opaque="opaque-value", stale=%t`,
+			qop, algorithm, nonce, stale,
+		))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	return server, &challenges
+}
+
+func TestClient_DigestAuth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		algorithm  string
+		qop        string
+		nonceUses  int
+		challenges int32
+	}{
+		{name: "MD5", algorithm: "MD5", qop: "auth", nonceUses: 10, challenges: 1},
+		{name: "SHA-256 with auth-int", algorithm: "SHA-256", qop: "auth-int", nonceUses: 10, challenges: 1},
+		{name: "stale nonce", algorithm: "MD5", qop: "auth", nonceUses: 2, challenges: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server, challenges := newDigestServer(t, tt.algorithm, tt.qop, "secret", tt.nonceUses)
+			client, err := NewClient(WithEndpoint(server.URL+"/service?wsdl"), WithDigestAuth("user", "secret"))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			for i := range 3 {
+				reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: fmt.Sprintf("hello %d", i)}))
+				var resp echoResponse
+				if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp); err != nil {
+					t.Fatalf("Client.CallDecode() error = %v", err)
+				}
+				if want := fmt.Sprintf("hello %d", i); resp.Message != want {
+					t.Errorf("Expected message %q, got: %q", want, resp.Message)
+				}
+			}
+			if got := challenges.Load(); got != tt.challenges {
+				t.Errorf("Expected %d challenges, got: %d", tt.challenges, got)
+			}
+		})
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		t.Parallel()
+		server, challenges := newDigestServer(t, "MD5", "auth", "secret", 10)
+		client, err := NewClient(WithEndpoint(server.URL), WithDigestAuth("user", "wrong"))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv)
+		var soapErr *Error
+		if !errors.As(err, &soapErr) || soapErr.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("Expected an HTTP 401 error, got: %v", err)
+		}
+		if got := challenges.Load(); got != 2 {
+			t.Errorf("Expected the challenge to be answered once, got %d challenges", got)
+		}
+	})
+}
+
+func TestParseAuthParams(t *testing.T) {
+	t.Parallel()
+	got := parseAuthParams(`realm="a \"b\", c", qop="auth,auth-int", algorithm=SHA-256 , stale=true`)
+	want := map[string]string{
+		"realm":     `a "b", c`,
+		"qop":       "auth,auth-int",
+		"algorithm": "SHA-256",
+		"stale":     "true",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("parseAuthParams() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+package soap
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff computes the delay before retrying a failed attempt.
+//
+// A Retry-After header in the failed response takes precedence over the
+// backoff policy.
+type Backoff interface {
+	// Delay returns the delay before the retry following the given failed
+	// attempt, starting at 1. previous is the delay before the failed attempt,
+	// or zero for the first attempt.
+	Delay(attempt int, previous time.Duration) time.Duration
+}
+
+// defaultBackoff is the backoff policy of clients without [WithBackoff].
+var defaultBackoff = ExponentialBackoff(250*time.Millisecond, 10*time.Second)
+
+// ConstantBackoff returns a policy waiting the same delay before every retry.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return constantBackoff{delay: delay}
+}
+
+type constantBackoff struct {
+	delay time.Duration
+}
+
+func (b constantBackoff) Delay(int, time.Duration) time.Duration {
+	return b.delay
+}
+
+// ExponentialBackoff returns a policy doubling the delay after every attempt,
+// starting at base and capped at maxDelay, with "full jitter": the actual
+// delay is random between zero and the exponential delay.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return exponentialBackoff{base: base, max: maxDelay}
+}
+
+type exponentialBackoff struct {
+	base, max time.Duration
+}
+
+func (b exponentialBackoff) Delay(attempt int, _ time.Duration) time.Duration {
+	v := math.Min(float64(b.max), float64(b.base)*math.Pow(2, float64(attempt-1)))
+	if v < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(v)))
+}
+
+// DecorrelatedJitterBackoff returns a policy picking each delay at random
+// between base and three times the previous delay, capped at maxDelay. It
+// spreads retries of concurrent clients better than exponential backoff.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func DecorrelatedJitterBackoff(base, maxDelay time.Duration) Backoff {
+	return decorrelatedJitterBackoff{base: base, max: maxDelay}
+}
+
+type decorrelatedJitterBackoff struct {
+	base, max time.Duration
+}
+
+func (b decorrelatedJitterBackoff) Delay(_ int, previous time.Duration) time.Duration {
+	upper := 3 * max(previous, b.base)
+	delay := b.base
+	if upper > b.base {
+		delay += time.Duration(rand.Int63n(int64(upper - b.base)))
+	}
+	return min(delay, b.max)
+}
+
+// RetryBudget limits retries to a share of the requests made, across all the
+// calls using it, so that retries do not multiply the traffic to a failing
+// service. Share a budget between calls by passing it to [WithRetryBudget].
+//
+// The budget is a token bucket: every call deposits ratio tokens, and every
+// retry withdraws one. A retry is skipped when the bucket holds less than one
+// token. The bucket starts full, holding burst tokens at most.
+type RetryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+// NewRetryBudget creates a retry budget allowing ratio retries per call, for
+// example 0.1 for one retry per ten calls, and bursts of up to burst retries.
+func NewRetryBudget(ratio float64, burst int) *RetryBudget {
+	return &RetryBudget{ratio: ratio, burst: float64(burst), tokens: float64(burst)}
+}
+
+// deposit adds the tokens earned by a call.
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+b.ratio)
+}
+
+// withdraw takes the token of a retry, reporting whether the budget allows it.
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// WithBackoff sets the backoff policy between retries. Defaults to
+// [ExponentialBackoff] with a 250ms base and a 10s cap.
+func WithBackoff(backoff Backoff) ClientOption {
+	return func(c *clientConfig) {
+		c.backoff = backoff
+	}
+}
+
+// WithMaxRetryElapsed limits the total time spent on a call across attempts.
+// A retry is not made when its delay would exceed the limit. Zero, the
+// default, means no limit.
+func WithMaxRetryElapsed(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.maxRetryElapsed = d
+	}
+}
+
+// WithRetryBudget limits retries by a budget, usually shared by all the calls
+// of a client. Calls out of budget return the error of their last attempt.
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *clientConfig) {
+		c.retryBudget = budget
+	}
+}
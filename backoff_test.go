@@ -0,0 +1,95 @@
+package soap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	if got := ConstantBackoff(time.Second).Delay(5, time.Second); got != time.Second {
+		t.Errorf("ConstantBackoff delay = %v, want 1s", got)
+	}
+	exponential := ExponentialBackoff(100*time.Millisecond, time.Second)
+	decorrelated := DecorrelatedJitterBackoff(100*time.Millisecond, time.Second)
+	var previous time.Duration
+	for attempt := 1; attempt <= 10; attempt++ {
+		limit := min(time.Second, 100*time.Millisecond<<(attempt-1))
+		if got := exponential.Delay(attempt, 0); got < 0 || got >= limit {
+			t.Errorf("ExponentialBackoff delay for attempt %d = %v, want in [0, %v)", attempt, got, limit)
+		}
+		got := decorrelated.Delay(attempt, previous)
+		if got < 100*time.Millisecond || got > time.Second || got > 3*max(previous, 100*time.Millisecond) {
+			t.Errorf("DecorrelatedJitterBackoff delay after %v = %v", previous, got)
+		}
+		previous = got
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+	budget := NewRetryBudget(0.5, 2)
+	if !budget.withdraw() || !budget.withdraw() {
+		t.Fatal("Expected the initial burst to allow 2 retries")
+	}
+	if budget.withdraw() {
+		t.Fatal("Expected the budget to be exhausted")
+	}
+	budget.deposit()
+	if budget.withdraw() {
+		t.Fatal("Expected one call to earn half a retry")
+	}
+	budget.deposit()
+	if !budget.withdraw() {
+		t.Fatal("Expected two calls to earn a retry")
+	}
+}
+
+func TestClient_RetryLimits(t *testing.T) {
+	t.Parallel()
+	var requestCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	tests := []struct {
+		name     string
+		opts     []ClientOption
+		requests int32
+	}{
+		{
+			name:     "max retries",
+			opts:     []ClientOption{WithMaxRetries(3), WithBackoff(ConstantBackoff(0))},
+			requests: 4,
+		},
+		{
+			name:     "max elapsed",
+			opts:     []ClientOption{WithMaxRetries(3), WithBackoff(ConstantBackoff(time.Second)), WithMaxRetryElapsed(time.Second / 2)},
+			requests: 1,
+		},
+		{
+			name:     "retry budget",
+			opts:     []ClientOption{WithMaxRetries(3), WithBackoff(ConstantBackoff(0)), WithRetryBudget(NewRetryBudget(0, 1))},
+			requests: 2,
+		},
+	}
+	for _, tt := range tests {
+		requestCount.Store(0)
+		client, err := NewClient(append([]ClientOption{WithEndpoint(server.URL)}, tt.opts...)...)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.Call(context.Background(), "urn:test", reqEnv); err == nil {
+			t.Fatalf("%s: expected an error", tt.name)
+		}
+		if got := requestCount.Load(); got != tt.requests {
+			t.Errorf("%s: expected %d requests, got %d", tt.name, tt.requests, got)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrBatchAborted is the error of the items of a batch that were not called
+// because an earlier item failed and the batch stops on errors. It is also the
+// cause of the context of the calls canceled then.
+var ErrBatchAborted = errors.New("soap: batch aborted after an error")
+
+// BatchResult is the outcome of an item of a batch.
+type BatchResult[T any] struct {
+	// Value is the result of the call, if it succeeded.
+	Value T
+	// Err is the error of the call, if it failed.
+	Err error
+}
+
+// BatchOption configures a batch using the functional options pattern.
+type BatchOption func(*batchConfig)
+
+// batchConfig holds the configuration of a batch.
+type batchConfig struct {
+	concurrency int
+	stopOnError bool
+	callOptions []ClientOption
+}
+
+// WithBatchConcurrency sets the maximum number of calls of a batch in flight
+// at once. Defaults to 10.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithBatchStopOnError controls whether a batch stops at the first failed
+// call. The contexts of the calls in flight are canceled with the cause
+// [ErrBatchAborted], and the remaining calls are not made and fail with
+// [ErrBatchAborted]. Calls that were made keep their own error. Defaults to
+// false.
+func WithBatchStopOnError(stop bool) BatchOption {
+	return func(c *batchConfig) {
+		c.stopOnError = stop
+	}
+}
+
+// WithBatchCallOptions applies client options to each call of a batch, such
+// as headers, timeouts or endpoints. They are applied by [Client.CallBatch]
+// and the batch methods of generated clients; see [BatchCallOptions].
+func WithBatchCallOptions(opts ...ClientOption) BatchOption {
+	return func(c *batchConfig) {
+		c.callOptions = append(c.callOptions, opts...)
+	}
+}
+
+// BatchCallOptions returns the client options of the calls of a batch, set
+// with [WithBatchCallOptions], for functions called by [Batch].
+func BatchCallOptions(opts ...BatchOption) []ClientOption {
+	var config batchConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config.callOptions
+}
+
+// Batch calls a function for each input, with bounded concurrency, and
+// returns the results in the order of the inputs. It returns when all calls
+// are done. Inputs not called because the context ended fail with the
+// context error.
+//
+// Batch runs the calls of operations of generated clients, which apply the
+// options of [WithBatchCallOptions] to each call; see [Client.CallBatch] for
+// calls of envelopes.
+func Batch[In, Out any](
+	ctx context.Context,
+	inputs []In,
+	call func(context.Context, In) (Out, error),
+	opts ...BatchOption,
+) []BatchResult[Out] {
+	config := batchConfig{concurrency: 10}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	results := make([]BatchResult[Out], len(inputs))
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(max(config.concurrency, 1), len(inputs)) {
+		wg.Go(func() {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(inputs) {
+					return
+				}
+				if ctx.Err() != nil {
+					results[i].Err = context.Cause(ctx)
+					continue
+				}
+				value, err := call(ctx, inputs[i])
+				if err != nil && config.stopOnError {
+					cancel(ErrBatchAborted)
+				}
+				results[i] = BatchResult[Out]{Value: value, Err: err}
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// BatchCall is a call of a batch made with [Client.CallBatch].
+type BatchCall struct {
+	// Action is the SOAP action of the call.
+	Action string
+	// Envelope is the request envelope.
+	Envelope *Envelope
+	// Options are the options of the call.
+	Options []ClientOption
+}
+
+// CallBatch makes many calls with bounded concurrency, like [Client.Call],
+// and returns the response envelopes in the order of the calls. The options
+// of each call apply after those of [WithBatchCallOptions].
+func (c *Client) CallBatch(ctx context.Context, calls []BatchCall, opts ...BatchOption) []BatchResult[*Envelope] {
+	callOptions := BatchCallOptions(opts...)
+	return Batch(ctx, calls, func(ctx context.Context, call BatchCall) (*Envelope, error) {
+		return c.Call(ctx, call.Action, call.Envelope, slices.Concat(callOptions, call.Options)...)
+	}, opts...)
+}
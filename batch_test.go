@@ -0,0 +1,134 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	inputs := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	errOdd := errors.New("odd")
+
+	t.Run("ordering and concurrency", func(t *testing.T) {
+		t.Parallel()
+		var inFlight, maxInFlight atomic.Int32
+		results := Batch(context.Background(), inputs, func(ctx context.Context, i int) (string, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			// Later items finish first.
+			time.Sleep(time.Duration(len(inputs)-i) * time.Millisecond)
+			if i%2 == 1 {
+				return "", errOdd
+			}
+			return fmt.Sprint(i), nil
+		}, WithBatchConcurrency(3))
+		if len(results) != len(inputs) {
+			t.Fatalf("Expected %d results, got: %d", len(inputs), len(results))
+		}
+		for i, result := range results {
+			if i%2 == 1 {
+				if !errors.Is(result.Err, errOdd) {
+					t.Errorf("Expected error for item %d, got: %v", i, result.Err)
+				}
+				continue
+			}
+			if result.Err != nil || result.Value != fmt.Sprint(i) {
+				t.Errorf("Expected value %d for item %d, got: %q, %v", i, i, result.Value, result.Err)
+			}
+		}
+		if got := maxInFlight.Load(); got > 3 {
+			t.Errorf("Expected at most 3 calls in flight, got: %d", got)
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		started := make(chan struct{})
+		results := Batch(context.Background(), inputs, func(ctx context.Context, i int) (int, error) {
+			calls.Add(1)
+			switch i {
+			case 0:
+				<-started
+				return 0, errOdd
+			case 1:
+				close(started)
+				<-ctx.Done()
+				if !errors.Is(context.Cause(ctx), ErrBatchAborted) {
+					t.Errorf("Expected the context cause to be ErrBatchAborted, got: %v", context.Cause(ctx))
+				}
+				return 0, ctx.Err()
+			}
+			return i, nil
+		}, WithBatchConcurrency(2), WithBatchStopOnError(true))
+		if !errors.Is(results[0].Err, errOdd) {
+			t.Errorf("Expected the failed call error, got: %v", results[0].Err)
+		}
+		if !errors.Is(results[1].Err, context.Canceled) {
+			t.Errorf("Expected the canceled call to keep its error, got: %v", results[1].Err)
+		}
+		for i, result := range results[2:] {
+			if !errors.Is(result.Err, ErrBatchAborted) {
+				t.Errorf("Expected item %d to be aborted, got: %v", i+2, result.Err)
+			}
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("Expected 2 calls, got: %d", got)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results := Batch(ctx, inputs, func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		for i, result := range results {
+			if !errors.Is(result.Err, context.Canceled) {
+				t.Errorf("Expected item %d to be canceled, got: %v", i, result.Err)
+			}
+		}
+	})
+}
+
+func TestClient_CallBatch(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(newEchoHandler())
+	t.Cleanup(server.Close)
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	calls := make([]BatchCall, 5)
+	for i := range calls {
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: fmt.Sprintf("hello %d", i)}))
+		calls[i] = BatchCall{Action: "urn:echo", Envelope: reqEnv}
+	}
+	results := client.CallBatch(context.Background(), calls, WithBatchConcurrency(2), WithBatchCallOptions(WithEndpoint(server.URL)))
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("Call %d error = %v", i, result.Err)
+		}
+		var resp echoResponse
+		if err := xml.Unmarshal(result.Value.Body.Content, &resp); err != nil {
+			t.Fatalf("Failed to decode response %d: %v", i, err)
+		}
+		if want := fmt.Sprintf("hello %d", i); resp.Message != want {
+			t.Errorf("Expected message %q, got: %q", want, resp.Message)
+		}
+	}
+}
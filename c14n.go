@@ -0,0 +1,346 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// xmlNamespace is the namespace bound to the reserved xml prefix.
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// xmlNode is an element of a parsed XML document. Unlike [xml.Decoder.Token],
+// it retains the namespace prefixes and declarations in scope, which are
+// needed for canonicalization.
+type xmlNode struct {
+	// prefix is the namespace prefix of the element as written.
+	prefix string
+	// name is the namespace-resolved name of the element.
+	name xml.Name
+	// attrs are the attributes of the element, excluding namespace declarations.
+	attrs []xmlNodeAttr
+	// namespaces maps the prefixes in scope to their namespace URIs.
+	// The default namespace is keyed by the empty prefix.
+	namespaces map[string]string
+	// children are the child nodes: *xmlNode, xml.CharData or xml.ProcInst.
+	children []any
+}
+
+// xmlNodeAttr is an attribute of an xmlNode.
+type xmlNodeAttr struct {
+	prefix string
+	name   xml.Name
+	value  string
+}
+
+// parseXMLNode parses an XML document into a tree of nodes, returning the root element.
+func parseXMLNode(data []byte) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			namespaces := map[string]string{}
+			if len(stack) > 0 {
+				namespaces = stack[len(stack)-1].namespaces
+			}
+			node := &xmlNode{prefix: t.Name.Space, namespaces: namespaces}
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					node.declare(attr.Name.Local, attr.Value)
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					node.declare("", attr.Value)
+				}
+			}
+			node.name = xml.Name{Space: node.lookup(t.Name.Space), Local: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				name := xml.Name{Local: attr.Name.Local}
+				if attr.Name.Space != "" {
+					// Unprefixed attributes are in no namespace, not the default namespace
+					name.Space = node.lookup(attr.Name.Space)
+				}
+				node.attrs = append(node.attrs, xmlNodeAttr{prefix: attr.Name.Space, name: name, value: attr.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			} else {
+				return nil, fmt.Errorf("multiple root elements")
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected end element %s", t.Name.Local)
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].children = append(stack[len(stack)-1].children, t.Copy())
+			}
+		case xml.ProcInst:
+			if len(stack) > 0 {
+				stack[len(stack)-1].children = append(stack[len(stack)-1].children, t.Copy())
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unclosed element %s", stack[len(stack)-1].name.Local)
+	}
+	return root, nil
+}
+
+// declare binds a prefix in the scope of the node.
+func (n *xmlNode) declare(prefix, namespace string) {
+	namespaces := make(map[string]string, len(n.namespaces)+1)
+	for p, ns := range n.namespaces {
+		namespaces[p] = ns
+	}
+	namespaces[prefix] = namespace
+	n.namespaces = namespaces
+}
+
+// lookup resolves a prefix to its namespace URI in the scope of the node.
+func (n *xmlNode) lookup(prefix string) string {
+	if prefix == "xml" {
+		return xmlNamespace
+	}
+	return n.namespaces[prefix]
+}
+
+// attr returns the value of the attribute with the given name.
+func (n *xmlNode) attr(space, local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.name.Space == space && attr.name.Local == local {
+			return attr.value, true
+		}
+	}
+	return "", false
+}
+
+// child returns the first child element with the given name.
+func (n *xmlNode) child(space, local string) *xmlNode {
+	for _, c := range n.children {
+		if node, ok := c.(*xmlNode); ok && node.name.Space == space && node.name.Local == local {
+			return node
+		}
+	}
+	return nil
+}
+
+// elements returns the child elements with the given name.
+func (n *xmlNode) elements(space, local string) []*xmlNode {
+	var result []*xmlNode
+	for _, c := range n.children {
+		if node, ok := c.(*xmlNode); ok && node.name.Space == space && node.name.Local == local {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// text returns the concatenated character data of the node's direct children.
+func (n *xmlNode) text() string {
+	var sb strings.Builder
+	for _, c := range n.children {
+		if data, ok := c.(xml.CharData); ok {
+			sb.Write(data)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// id returns the identifier of the node, as used by same-document references.
+// wsu:Id is preferred, with unqualified Id, ID and id attributes as fallbacks.
+func (n *xmlNode) id() string {
+	if id, ok := n.attr(WSUNamespace, "Id"); ok {
+		return id
+	}
+	for _, local := range []string{"Id", "ID", "id"} {
+		if id, ok := n.attr("", local); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// findByID returns the element in the subtree with the given identifier.
+// It returns an error when the identifier is missing or ambiguous, as
+// duplicate identifiers are a known vector for signature wrapping attacks.
+func (n *xmlNode) findByID(id string) (*xmlNode, error) {
+	var matches []*xmlNode
+	var walk func(*xmlNode)
+	walk = func(node *xmlNode) {
+		if node.id() == id {
+			matches = append(matches, node)
+		}
+		for _, c := range node.children {
+			if child, ok := c.(*xmlNode); ok {
+				walk(child)
+			}
+		}
+	}
+	walk(n)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no element with id %q", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("multiple elements with id %q", id)
+	}
+}
+
+// canonicalize serializes the subtree rooted at the node using Exclusive XML
+// Canonicalization 1.0 without comments. Prefixes listed in inclusive are
+// treated as in the InclusiveNamespaces PrefixList.
+func (n *xmlNode) canonicalize(inclusive ...string) []byte {
+	var buf bytes.Buffer
+	n.writeCanonical(&buf, map[string]string{"": ""}, inclusive)
+	return buf.Bytes()
+}
+
+// writeCanonical writes the canonical form of the node, given the namespace
+// declarations already rendered by its output ancestors.
+func (n *xmlNode) writeCanonical(buf *bytes.Buffer, rendered map[string]string, inclusive []string) {
+	// Only visibly utilized namespaces are rendered, where they differ from
+	// what the nearest output ancestor already rendered.
+	used := []string{n.prefix}
+	for _, attr := range n.attrs {
+		if attr.prefix != "" && !slices.Contains(used, attr.prefix) {
+			used = append(used, attr.prefix)
+		}
+	}
+	for _, prefix := range inclusive {
+		if _, ok := n.namespaces[prefix]; ok && !slices.Contains(used, prefix) {
+			used = append(used, prefix)
+		}
+	}
+	var declared []string
+	next := rendered
+	for _, prefix := range used {
+		if prefix == "xml" {
+			continue
+		}
+		namespace := n.namespaces[prefix]
+		if previous, ok := rendered[prefix]; ok && previous == namespace {
+			continue
+		}
+		if len(declared) == 0 {
+			next = make(map[string]string, len(rendered)+1)
+			for p, ns := range rendered {
+				next[p] = ns
+			}
+		}
+		declared = append(declared, prefix)
+		next[prefix] = namespace
+	}
+	slices.Sort(declared)
+	attrs := slices.Clone(n.attrs)
+	slices.SortFunc(attrs, func(a, b xmlNodeAttr) int {
+		if c := strings.Compare(a.name.Space, b.name.Space); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name.Local, b.name.Local)
+	})
+	qname := qualifiedName(n.prefix, n.name.Local)
+	buf.WriteString("<" + qname)
+	for _, prefix := range declared {
+		if prefix == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + prefix + `="`)
+		}
+		writeCanonicalAttrValue(buf, next[prefix])
+		buf.WriteString(`"`)
+	}
+	for _, attr := range attrs {
+		buf.WriteString(" " + qualifiedName(attr.prefix, attr.name.Local) + `="`)
+		writeCanonicalAttrValue(buf, attr.value)
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	for _, c := range n.children {
+		switch child := c.(type) {
+		case *xmlNode:
+			child.writeCanonical(buf, next, inclusive)
+		case xml.CharData:
+			writeCanonicalText(buf, string(child))
+		case xml.ProcInst:
+			buf.WriteString("<?" + child.Target)
+			if len(child.Inst) > 0 {
+				buf.WriteString(" ")
+				buf.Write(child.Inst)
+			}
+			buf.WriteString("?>")
+		}
+	}
+	buf.WriteString("</" + qname + ">")
+}
+
+// qualifiedName joins a prefix and local name.
+func qualifiedName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+// writeCanonicalText writes character data escaped as per C14N.
+func writeCanonicalText(buf *bytes.Buffer, text string) {
+	for _, r := range text {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
+
+// writeCanonicalAttrValue writes an attribute value escaped as per C14N.
+func writeCanonicalAttrValue(buf *bytes.Buffer, value string) {
+	for _, r := range value {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '"':
+			buf.WriteString("&quot;")
+		case '\t':
+			buf.WriteString("&#x9;")
+		case '\n':
+			buf.WriteString("&#xA;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
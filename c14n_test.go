@@ -0,0 +1,85 @@
+package soap
+
+import (
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		input    string
+		id       string
+		expected string
+	}{
+		{
+			// Example from the Exclusive XML Canonicalization 1.0 specification, section 2.2
+			name: "exclusive namespace rendering",
+			input: `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org">` +
+				`<n1:elem2 xmlns:n1="http://example.net" xml:lang="en" Id="target">` +
+				`<n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`,
+			id: "target",
+			expected: `<n1:elem2 xmlns:n1="http://example.net" Id="target" xml:lang="en">` +
+				`<n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2>`,
+		},
+		{
+			name: "attribute and namespace ordering",
+			input: `<root xmlns="urn:default" xmlns:z="urn:z" xmlns:a="urn:a">` +
+				`<e Id="target" z:c="3" b="2" a:d="4" a="1"><child/></e></root>`,
+			id: "target",
+			expected: `<e xmlns="urn:default" xmlns:a="urn:a" xmlns:z="urn:z" Id="target" a="1" b="2" a:d="4" z:c="3">` +
+				`<child></child></e>`,
+		},
+		{
+			name:     "redundant and unused declarations",
+			input:    `<a:root xmlns:a="urn:a" xmlns:b="urn:b" Id="target"><a:child xmlns:a="urn:a"><b:x/></a:child></a:root>`,
+			id:       "target",
+			expected: `<a:root xmlns:a="urn:a" Id="target"><a:child><b:x xmlns:b="urn:b"></b:x></a:child></a:root>`,
+		},
+		{
+			name:     "default namespace undeclared",
+			input:    `<root xmlns="urn:default"><x:e xmlns:x="urn:x" Id="target"><plain xmlns=""/></x:e></root>`,
+			id:       "target",
+			expected: `<x:e xmlns:x="urn:x" Id="target"><plain></plain></x:e>`,
+		},
+		{
+			name:     "text and attribute escaping",
+			input:    "<e Id=\"target\" v=\"a&lt;b&amp;&quot;c&#9;\">1 &lt; 2 &amp;&amp; 3 &gt; 2<![CDATA[ <x> ]]></e>",
+			id:       "target",
+			expected: "<e Id=\"target\" v=\"a&lt;b&amp;&quot;c&#x9;\">1 &lt; 2 &amp;&amp; 3 &gt; 2 &lt;x&gt; </e>",
+		},
+		{
+			name:     "comments removed",
+			input:    `<e Id="target"><!-- comment -->text</e>`,
+			id:       "target",
+			expected: `<e Id="target">text</e>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root, err := parseXMLNode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parseXMLNode() error = %v", err)
+			}
+			node, err := root.findByID(tt.id)
+			if err != nil {
+				t.Fatalf("findByID() error = %v", err)
+			}
+			if got := string(node.canonicalize()); got != tt.expected {
+				t.Errorf("canonicalize() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindByID_Duplicate(t *testing.T) {
+	t.Parallel()
+	root, err := parseXMLNode([]byte(`<root><a Id="x"/><b Id="x"/></root>`))
+	if err != nil {
+		t.Fatalf("parseXMLNode() error = %v", err)
+	}
+	if _, err := root.findByID("x"); err == nil {
+		t.Error("Expected an error for duplicate identifiers")
+	}
+}
@@ -0,0 +1,302 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for requests rejected by an open [CircuitBreaker].
+var ErrCircuitOpen = errors.New("soap: circuit breaker is open")
+
+// CircuitState is the state of a circuit of a [CircuitBreaker].
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with [ErrCircuitOpen].
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through, and
+	// closes the circuit when one succeeds.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitThresholds are the numbers of consecutive failures of each kind that
+// trip a circuit open. A zero threshold ignores failures of that kind, which
+// then count as successes. Any successful request resets the counts.
+type CircuitThresholds struct {
+	// HTTPErrors counts network errors and HTTP 5xx responses that are not
+	// SOAP faults.
+	HTTPErrors int
+	// Timeouts counts requests that timed out.
+	Timeouts int
+	// ServerFaults counts Server (SOAP 1.2: Receiver) faults. Other faults
+	// report errors of the request, and count as successes.
+	ServerFaults int
+}
+
+// CircuitBreaker stops sending requests to a failing service for a while,
+// failing fast with [ErrCircuitOpen] instead.
+//
+// Requests are grouped into circuits by a key, by default a single circuit
+// for all requests. A circuit trips open when consecutive failures reach the
+// thresholds. After the open timeout, it turns half-open and lets probe
+// requests through: a successful probe closes the circuit, a failed one opens
+// it again. A breaker tracks state across calls; share it between calls by
+// passing it to [WithCircuitBreaker].
+type CircuitBreaker struct {
+	thresholds     CircuitThresholds
+	openTimeout    time.Duration
+	halfOpenProbes int
+	key            func(*http.Request) string
+	mu             sync.Mutex
+	circuits       map[string]*circuit
+}
+
+// circuit is the state of the requests sharing a key.
+type circuit struct {
+	state    CircuitState
+	failures CircuitThresholds
+	openedAt time.Time
+	inFlight int
+}
+
+// CircuitBreakerOption configures a CircuitBreaker using the functional options pattern.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithCircuitThresholds sets the consecutive failures that trip a circuit.
+// Defaults to 5 of each kind.
+func WithCircuitThresholds(thresholds CircuitThresholds) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.thresholds = thresholds
+	}
+}
+
+// WithOpenTimeout sets how long a circuit stays open before probe requests
+// are let through. Defaults to 30s.
+func WithOpenTimeout(d time.Duration) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.openTimeout = d
+	}
+}
+
+// WithHalfOpenProbes sets the number of concurrent probe requests of a
+// half-open circuit. Defaults to 1.
+func WithHalfOpenProbes(n int) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.halfOpenProbes = n
+	}
+}
+
+// WithCircuitKey sets the function grouping requests into circuits, such as
+// [CircuitPerEndpoint] or [CircuitPerAction].
+func WithCircuitKey(key func(*http.Request) string) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.key = key
+	}
+}
+
+// CircuitPerEndpoint keys circuits by endpoint URL.
+func CircuitPerEndpoint(req *http.Request) string {
+	return req.URL.String()
+}
+
+// CircuitPerAction keys circuits by SOAP action, from the SOAPAction header
+// or the action parameter of the SOAP 1.2 content type.
+func CircuitPerAction(req *http.Request) string {
+	return httpRequestAction(req)
+}
+
+// httpRequestAction returns the SOAP action of an outgoing request.
+func httpRequestAction(req *http.Request) string {
+	if action := strings.Trim(req.Header.Get("SOAPAction"), `"`); action != "" {
+		return action
+	}
+	_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return params["action"]
+}
+
+// NewCircuitBreaker creates a circuit breaker with the specified options.
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		thresholds:     CircuitThresholds{HTTPErrors: 5, Timeouts: 5, ServerFaults: 5},
+		openTimeout:    30 * time.Second,
+		halfOpenProbes: 1,
+		key:            func(*http.Request) string { return "" },
+		circuits:       make(map[string]*circuit),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithCircuitBreaker guards requests with a circuit breaker. Each attempt of a
+// call goes through the breaker, so retries stop when the circuit opens, and
+// with an endpoint pool an open circuit fails over to the next endpoint.
+func WithCircuitBreaker(breaker *CircuitBreaker) ClientOption {
+	return func(c *clientConfig) {
+		c.circuitBreaker = breaker
+	}
+}
+
+// State returns the state of the circuit with the key. The key of the default
+// single circuit is the empty string.
+func (b *CircuitBreaker) State(key string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		return CircuitClosed
+	}
+	return b.currentState(c, time.Now())
+}
+
+// States returns the state of every circuit that has seen requests, by key.
+func (b *CircuitBreaker) States() map[string]CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	states := make(map[string]CircuitState, len(b.circuits))
+	for key, c := range b.circuits {
+		states[key] = b.currentState(c, now)
+	}
+	return states
+}
+
+// currentState returns the state of a circuit, turning it half-open when its
+// open timeout elapsed. The caller must hold the lock.
+func (b *CircuitBreaker) currentState(c *circuit, now time.Time) CircuitState {
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= b.openTimeout {
+		c.state = CircuitHalfOpen
+		c.inFlight = 0
+	}
+	return c.state
+}
+
+// allow reports whether a request with the key may be sent, and registers it.
+func (b *CircuitBreaker) allow(key string) (*circuit, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	switch b.currentState(c, time.Now()) {
+	case CircuitOpen:
+		return nil, false
+	case CircuitHalfOpen:
+		if c.inFlight >= b.halfOpenProbes {
+			return nil, false
+		}
+	}
+	c.inFlight++
+	return c, true
+}
+
+// record updates a circuit with the outcome of a request.
+func (b *CircuitBreaker) record(c *circuit, resp *http.Response, err error) {
+	failures := CircuitThresholds{}
+	switch {
+	case err != nil && (errors.Is(err, context.DeadlineExceeded) || isTimeoutErr(err)):
+		failures.Timeouts = 1
+	case err != nil && errors.Is(err, context.Canceled):
+		// Canceled requests say nothing about the health of the service
+		b.mu.Lock()
+		c.release()
+		b.mu.Unlock()
+		return
+	case err != nil:
+		failures.HTTPErrors = 1
+	case resp.StatusCode >= 500:
+		if fault := peekFault(resp); fault == nil {
+			failures.HTTPErrors = 1
+		} else if isServerFault(fault) {
+			failures.ServerFaults = 1
+		}
+	}
+	// Failures of ignored kinds count as successes
+	if b.thresholds.HTTPErrors == 0 {
+		failures.HTTPErrors = 0
+	}
+	if b.thresholds.Timeouts == 0 {
+		failures.Timeouts = 0
+	}
+	if b.thresholds.ServerFaults == 0 {
+		failures.ServerFaults = 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c.release()
+	if failures == (CircuitThresholds{}) {
+		c.state = CircuitClosed
+		c.failures = CircuitThresholds{}
+		return
+	}
+	c.failures.HTTPErrors += failures.HTTPErrors
+	c.failures.Timeouts += failures.Timeouts
+	c.failures.ServerFaults += failures.ServerFaults
+	if c.state == CircuitHalfOpen || b.tripped(c.failures) {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+// release unregisters a finished request. Requests sent before the circuit
+// turned half-open are not counted as probes.
+func (c *circuit) release() {
+	if c.inFlight > 0 {
+		c.inFlight--
+	}
+}
+
+// tripped reports whether failure counts reach any of the thresholds.
+func (b *CircuitBreaker) tripped(failures CircuitThresholds) bool {
+	return (b.thresholds.HTTPErrors > 0 && failures.HTTPErrors >= b.thresholds.HTTPErrors) ||
+		(b.thresholds.Timeouts > 0 && failures.Timeouts >= b.thresholds.Timeouts) ||
+		(b.thresholds.ServerFaults > 0 && failures.ServerFaults >= b.thresholds.ServerFaults)
+}
+
+// circuitBreakerTransport guards attempts with a circuit breaker.
+type circuitBreakerTransport struct {
+	breaker *CircuitBreaker
+	next    http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.breaker.key(req)
+	c, ok := t.breaker.allow(key)
+	if !ok {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		if key == "" {
+			return nil, ErrCircuitOpen
+		}
+		return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, key)
+	}
+	resp, err := t.next.RoundTrip(req)
+	t.breaker.record(c, resp, err)
+	return resp, err
+}
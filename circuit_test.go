@@ -0,0 +1,105 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	breaker := NewCircuitBreaker(
+		WithCircuitThresholds(CircuitThresholds{HTTPErrors: 2}),
+		WithOpenTimeout(50*time.Millisecond),
+	)
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithCircuitBreaker(breaker))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	call := func() error {
+		_, err := client.Call(context.Background(), "urn:test", reqEnv)
+		return err
+	}
+	for range 2 {
+		if err := call(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected an HTTP error, got: %v", err)
+		}
+	}
+	if state := breaker.State(""); state != CircuitOpen {
+		t.Fatalf("Expected an open circuit, got %v", state)
+	}
+	if err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the open circuit to fail fast, got %d requests", got)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if state := breaker.States()[""]; state != CircuitHalfOpen {
+		t.Fatalf("Expected a half-open circuit, got %v", state)
+	}
+	healthy.Store(true)
+	if err := call(); err != nil {
+		t.Fatalf("Expected the probe to succeed, got: %v", err)
+	}
+	if state := breaker.State(""); state != CircuitClosed {
+		t.Errorf("Expected a closed circuit, got %v", state)
+	}
+}
+
+func TestCircuitBreaker_Faults(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := "soapenv:Server"
+		if r.Header.Get("SOAPAction") == "urn:invalid" {
+			code = "soapenv:Client"
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+			`<soapenv:Fault><faultcode>` + code + `</faultcode><faultstring>failed</faultstring></soapenv:Fault>` +
+			`</soapenv:Body></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	breaker := NewCircuitBreaker(
+		WithCircuitThresholds(CircuitThresholds{ServerFaults: 2}),
+		WithCircuitKey(CircuitPerAction),
+	)
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithCircuitBreaker(breaker))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	for range 3 {
+		_, _ = client.Call(context.Background(), "urn:invalid", reqEnv)
+		_, _ = client.Call(context.Background(), "urn:failing", reqEnv)
+	}
+	states := breaker.States()
+	if states["urn:invalid"] != CircuitClosed {
+		t.Errorf("Expected client faults to keep the circuit closed, got %v", states["urn:invalid"])
+	}
+	if states["urn:failing"] != CircuitOpen {
+		t.Errorf("Expected server faults to open the circuit, got %v", states["urn:failing"])
+	}
+	var soapErr *Error
+	if _, err := client.Call(context.Background(), "urn:invalid", reqEnv); !errors.As(err, &soapErr) || soapErr.Fault == nil {
+		t.Errorf("Expected the fault to be decoded after the breaker peeked at it, got: %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"maps"
+	"mime"
 	"net/http"
 	"runtime/debug"
 	"time"
@@ -24,36 +26,73 @@ type ClientOption func(*clientConfig)
 // clientConfig holds the configuration for a Client.
 type clientConfig struct {
 	endpoint          string
+	version           Version
+	mtom              bool
+	xopElements       []string
+	security          securityConfig
+	addressing        *Addressing
+	headers           []headerValue
+	responseHeaders   []responseHeader
 	httpClient        *http.Client
 	addXMLDeclaration bool
 	maxRetries        int
 	timeout           time.Duration
 	interceptors      []func(http.RoundTripper) http.RoundTripper
+	middleware        []Middleware
 	checkRetry        func(context.Context, error, *http.Request, *http.Response) bool
+	maxResponseBytes  int64
+	trace             *ClientTrace
+	idempotent        bool
+	backoff           Backoff
+	maxRetryElapsed   time.Duration
+	retryBudget       *RetryBudget
+	endpointPool      *EndpointPool
+	circuitBreaker    *CircuitBreaker
+	rateLimits        []*rateLimit
+	auth              challengeAuth
+	tls               *tlsOptions
+	tlsTransport      http.RoundTripper
+	err               error
 }
 
 // newClientConfig creates a new clientConfig with default values.
 func newClientConfig() clientConfig {
 	return clientConfig{
 		endpoint:          "",
+		version:           Version11,
 		addXMLDeclaration: true,
 		maxRetries:        3,
 		timeout:           30 * time.Second,
 	}
 }
 
-// WithEndpoint sets the default SOAP endpoint URL.
-// This can be overridden per call if needed in the future.
+// WithEndpoint sets the SOAP endpoint URL. It replaces the endpoints set
+// with [WithEndpointPool].
 func WithEndpoint(endpoint string) ClientOption {
 	return func(c *clientConfig) {
 		c.endpoint = endpoint
+		c.endpointPool = nil
+	}
+}
+
+// WithSOAPVersion sets the SOAP version used for the HTTP binding.
+// Defaults to [Version11].
+//
+// SOAP 1.1 requests are sent as text/xml with a SOAPAction header, SOAP 1.2
+// requests as application/soap+xml with the action as a content type parameter.
+// The envelope namespace is not changed, use [WithVersion] when creating
+// envelopes for SOAP 1.2 services.
+func WithSOAPVersion(version Version) ClientOption {
+	return func(c *clientConfig) {
+		c.version = version
 	}
 }
 
 // WithHTTPClient sets the base HTTP client whose transport is used as the
 // innermost layer of the transport chain (interceptors and retry wrap it).
 // If the client has no Transport, [http.DefaultTransport] is used.
-// The client's Timeout is ignored — use [WithTimeout] instead.
+// The client's Timeout is ignored — use [WithTimeout] instead; its other
+// settings, such as Jar and CheckRedirect, are kept.
 func WithHTTPClient(client *http.Client) ClientOption {
 	return func(c *clientConfig) {
 		c.httpClient = client
@@ -102,6 +141,24 @@ func WithMaxRetries(retries int) ClientOption {
 	}
 }
 
+// WithIdempotent declares whether calls are idempotent. Since SOAP requests
+// always use POST, [DefaultCheckRetry] only retries server errors and timeouts
+// of calls declared idempotent. Use it per call for idempotent operations.
+func WithIdempotent(idempotent bool) ClientOption {
+	return func(c *clientConfig) {
+		c.idempotent = idempotent
+	}
+}
+
+// WithMaxResponseBytes limits the size of response bodies read by the client.
+// Calls whose response exceeds the limit fail with a [*ResponseTooLargeError]
+// instead of reading the rest of the body. Zero, the default, means no limit.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *clientConfig) {
+		c.maxResponseBytes = n
+	}
+}
+
 // WithInterceptor adds a request interceptor for the Client.
 func WithInterceptor(interceptor func(http.RoundTripper) http.RoundTripper) ClientOption {
 	return func(c *clientConfig) {
@@ -118,74 +175,247 @@ func WithCheckRetry(checkRetry func(context.Context, error, *http.Request, *http
 }
 
 // with returns a new clientConfig with the given options applied.
-// This enables per-request configuration overrides.
 func (c clientConfig) with(opts ...ClientOption) clientConfig {
+	previous := c
 	for _, opt := range opts {
 		opt(&c)
 	}
+	c.updateTLSTransport(previous)
 	return c
 }
 
+// withCall returns a new clientConfig with the options of a call applied.
+// This enables per-request configuration overrides, except of TLS options,
+// which would need new transports and connections for every call.
+func (c clientConfig) withCall(opts ...ClientOption) clientConfig {
+	previous := c
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.tls != previous.tls {
+		if c.err == nil {
+			c.err = errTLSCallOption
+		}
+		return c
+	}
+	c.updateTLSTransport(previous)
+	return c
+}
+
+// updateTLSTransport builds the TLS transport once per TLS options and base
+// transport, so that connections are reused across calls, also of calls with
+// their own HTTP client.
+func (c *clientConfig) updateTLSTransport(previous clientConfig) {
+	if c.tls != nil && (c.tls != previous.tls || c.httpClient != previous.httpClient) {
+		var base http.RoundTripper
+		if c.httpClient != nil {
+			base = c.httpClient.Transport
+		}
+		transport, err := c.tls.cachedTransport(base)
+		if err != nil && c.err == nil {
+			c.err = err
+		}
+		c.tlsTransport = transport
+	}
+}
+
+// withError returns an option failing the configuration with an error, for
+// options whose arguments are invalid.
+func withError(err error) ClientOption {
+	return func(c *clientConfig) {
+		if c.err == nil {
+			c.err = err
+		}
+	}
+}
+
 // NewClient creates a new SOAP client with the specified options.
 // Returns an error if the configuration is invalid.
 func NewClient(opts ...ClientOption) (*Client, error) {
-	config := newClientConfig()
-	for _, opt := range opts {
-		opt(&config)
+	config := newClientConfig().with(opts...)
+	if config.err != nil {
+		return nil, config.err
 	}
 	return &Client{config: config}, nil
 }
 
 // Call executes a SOAP request with the provided action, envelope, and call-specific options.
+//
+// The response is read into memory in full. Use [Client.CallDecode] to decode
+// large responses while they are read.
 func (c *Client) Call(
 	ctx context.Context,
 	action string,
 	requestEnvelope *Envelope,
 	opts ...ClientOption,
+) (_ *Envelope, err error) {
+	config := c.config.withCall(opts...)
+	ctx, tracer := startCallTrace(ctx, action, config)
+	defer func() { tracer.done(ctx, err) }()
+	var responseEnvelope *Envelope
+	if len(config.middleware) > 0 {
+		responseEnvelope, err = config.invoke(ctx, action, requestEnvelope, c.invoker(config))
+	} else {
+		responseEnvelope, err = c.roundTrip(ctx, action, requestEnvelope, config)
+	}
+	if err := config.decodeResponseHeaders(responseEnvelope, err); err != nil {
+		return nil, err
+	}
+	return responseEnvelope, nil
+}
+
+// invoker returns the innermost invoker of the middleware of a configuration.
+func (c *Client) invoker(config clientConfig) Invoker {
+	return func(ctx context.Context, action string, req *Envelope) (*Envelope, error) {
+		return c.roundTrip(ctx, action, req, config)
+	}
+}
+
+// roundTrip sends the request envelope and reads the response envelope.
+func (c *Client) roundTrip(
+	ctx context.Context,
+	action string,
+	requestEnvelope *Envelope,
+	config clientConfig,
 ) (*Envelope, error) {
-	config := c.config.with(opts...)
-	xmlData, err := xml.Marshal(requestEnvelope)
+	resp, messageID, err := c.send(ctx, action, requestEnvelope, config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	responseEnvelope, err := readResponse(resp, config)
+	if err != nil {
+		return nil, err
+	}
+	if config.addressing != nil {
+		if err := checkRelatesTo(responseEnvelope, messageID, config.addressing); err != nil {
+			return nil, err
+		}
+	}
+	return responseEnvelope, nil
+}
+
+// prepareEnvelope returns a copy of the request envelope with the headers
+// configured on the client added, and the WS-Addressing message ID if any.
+// The caller's envelope is never modified.
+func prepareEnvelope(env *Envelope, action string, config clientConfig) (*Envelope, string, error) {
+	env = env.clone()
+	for _, header := range config.headers {
+		if err := env.AddHeader(header.value, header.mustUnderstand, header.actor); err != nil {
+			return nil, "", err
+		}
+	}
+	var messageID string
+	if config.addressing != nil {
+		var err error
+		if messageID, err = applyAddressing(env, action, config.endpoint, config.addressing); err != nil {
+			return nil, "", fmt.Errorf("failed to add WS-Addressing headers: %w", err)
+		}
+	}
+	return env, messageID, nil
+}
+
+// requestEncoder encodes the body of a request, and returns it with its
+// Content-Type.
+type requestEncoder func() ([]byte, string, error)
+
+// requestEncoderKey is the context key of the encoder of requests whose body
+// must be encoded again for each attempt.
+type requestEncoderKey struct{}
+
+// encodeRequest encodes a prepared request envelope into an HTTP body. The
+// WS-Security header is added last, so that a signature covers the final
+// envelope, and anew on each call, so that every attempt has its own nonce
+// and timestamps.
+func encodeRequest(env *Envelope, action string, config clientConfig) ([]byte, string, error) {
+	if config.security.enabled() {
+		env = env.clone()
+		if err := applySecurity(env, &config.security); err != nil {
+			return nil, "", fmt.Errorf("failed to add WS-Security header: %w", err)
+		}
+	}
+	xmlData, err := xml.Marshal(env)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal SOAP envelope: %w", err)
+		return nil, "", fmt.Errorf("failed to marshal SOAP envelope: %w", err)
 	}
 	if config.addXMLDeclaration {
 		xmlData = addXMLDeclaration(xmlData)
 	}
-	bodyReader := bytes.NewReader(xmlData)
-	return c.doRequest(ctx, action, bodyReader, config)
+	if !config.mtom && len(env.Attachments) == 0 {
+		return xmlData, contentType(config.version, action), nil
+	}
+	body, mediaType, err := encodeMultipartRelated(xmlData, config.version, action, config.mtom, config.xopElements, env.Attachments)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode multipart message: %w", err)
+	}
+	return body, mediaType, nil
 }
 
-// doRequest performs a single SOAP request.
-func (c *Client) doRequest(
+// send encodes the request envelope and performs the HTTP request. It returns
+// the HTTP response, whose body must be closed by the caller, and the
+// WS-Addressing message ID of the request if any.
+func (c *Client) send(
 	ctx context.Context,
 	action string,
-	body io.Reader,
+	requestEnvelope *Envelope,
 	config clientConfig,
-) (*Envelope, error) {
+) (*http.Response, string, error) {
+	if config.err != nil {
+		return nil, "", config.err
+	}
 	if config.endpoint == "" {
-		return nil, fmt.Errorf("endpoint is required")
+		return nil, "", fmt.Errorf("endpoint is required")
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", config.endpoint, body)
+	requestEnvelope, messageID, err := prepareEnvelope(requestEnvelope, action, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, "", err
 	}
-	if action != "" {
-		req.Header.Set("SOAPAction", action)
+	encode := requestEncoder(func() ([]byte, string, error) {
+		return encodeRequest(requestEnvelope, action, config)
+	})
+	body, mediaType, err := encode()
+	if err != nil {
+		return nil, "", err
+	}
+	callTracerFrom(ctx).requestMarshalled(ctx, len(body))
+	if config.idempotent {
+		ctx = context.WithValue(ctx, idempotentKey{}, true)
+	}
+	if config.security.enabled() {
+		// Retries must not replay the nonce and timestamps of the Security header
+		ctx = context.WithValue(ctx, requestEncoderKey{}, encode)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", config.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	req.Header.Set("User-Agent", getUserAgent())
-	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("Content-Type", mediaType)
+	if action != "" && config.version != Version12 {
+		req.Header.Set("SOAPAction", action)
+	}
 	httpClient := c.httpClient(config)
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+		return nil, "", fmt.Errorf("failed to execute HTTP request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-	respBody, err := io.ReadAll(resp.Body)
+	return resp, messageID, nil
+}
+
+// readResponse reads the HTTP response body into memory and decodes the
+// response envelope.
+func readResponse(resp *http.Response, config clientConfig) (*Envelope, error) {
+	respBody, err := io.ReadAll(limitResponseBody(resp.Body, config.maxResponseBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	var env Envelope
-	if xmlErr := xml.Unmarshal(respBody, &env); xmlErr != nil {
+	envelopeXML, attachments, xmlErr := decodeResponseBody(resp.Header.Get("Content-Type"), respBody)
+	if xmlErr == nil {
+		xmlErr = xml.Unmarshal(envelopeXML, &env)
+		env.Attachments = attachments
+	}
+	if xmlErr != nil {
 		// Not a valid SOAP envelope, but we might still have a useful HTTP error
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			return nil, &Error{
@@ -204,6 +434,11 @@ func (c *Client) doRequest(
 			Fault:        fault,
 		}
 	}
+	if roots := config.security.verificationRoots; roots != nil {
+		if err := verifyEnvelopeSignature(envelopeXML, roots, config.security.clock()); err != nil {
+			return nil, err
+		}
+	}
 	return &env, nil
 }
 
@@ -214,9 +449,17 @@ func (c *Client) httpClient(cfg clientConfig) *http.Client {
 	if cfg.httpClient != nil {
 		transport = cfg.httpClient.Transport
 	}
+	if cfg.tlsTransport != nil {
+		transport = cfg.tlsTransport
+	}
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
+	// Add auth transport innermost, so that every leg of a handshake goes to
+	// the same endpoint and carries the headers set by interceptors.
+	if cfg.auth != nil {
+		transport = &authTransport{auth: cfg.auth, next: transport}
+	}
 	// Add middleware transport if middlewares are configured.
 	if len(cfg.interceptors) > 0 {
 		transport = &interceptorTransport{
@@ -224,18 +467,59 @@ func (c *Client) httpClient(cfg clientConfig) *http.Client {
 			next:         transport,
 		}
 	}
+	// Add trace transport inside retries, so that each attempt is traced.
+	if cfg.trace != nil {
+		transport = &traceTransport{next: transport}
+	}
+	// Add rate limit transport inside failover, so that limits can apply to
+	// the endpoint of each attempt.
+	if len(cfg.rateLimits) > 0 {
+		transport = &rateLimitTransport{limits: cfg.rateLimits, next: transport}
+	}
+	// Add circuit breaker transport inside failover, so that circuits can be
+	// keyed by the endpoint of each attempt.
+	if cfg.circuitBreaker != nil {
+		transport = &circuitBreakerTransport{breaker: cfg.circuitBreaker, next: transport}
+	}
+	// Add endpoint transport inside retries, so that each attempt fails over.
+	if cfg.endpointPool != nil {
+		transport = &endpointTransport{pool: cfg.endpointPool, next: transport}
+	}
 	// Add retry transport if retry count > 0.
 	if cfg.maxRetries > 0 {
 		transport = &retryTransport{
 			maxRetries:  cfg.maxRetries,
 			next:        transport,
 			shouldRetry: cfg.checkRetry,
+			backoff:     cfg.backoff,
+			maxElapsed:  cfg.maxRetryElapsed,
+			budget:      cfg.retryBudget,
 		}
 	}
-	return &http.Client{
-		Timeout:   cfg.timeout,
-		Transport: transport,
+	// Keep the other settings of an injected client, such as its cookie jar
+	// and redirect policy.
+	client := &http.Client{}
+	if cfg.httpClient != nil {
+		*client = *cfg.httpClient
+	}
+	client.Timeout = cfg.timeout
+	client.Transport = transport
+	return client
+}
+
+// contentType returns the HTTP Content-Type for a SOAP request of the given version.
+// SOAP 1.2 carries the action as a media type parameter instead of a SOAPAction header.
+func contentType(version Version, action string) string {
+	if version != Version12 {
+		return "text/xml; charset=utf-8"
+	}
+	if action == "" {
+		return "application/soap+xml; charset=utf-8"
 	}
+	return mime.FormatMediaType("application/soap+xml", map[string]string{
+		"charset": "utf-8",
+		"action":  action,
+	})
 }
 
 // addXMLDeclaration adds an XML declaration to the beginning of XML data if it doesn't already have one.
@@ -256,11 +540,20 @@ func checkForSOAPFault(envelope *Envelope) *Fault {
 		return nil
 	}
 	if fault.XMLName.Local == "Fault" && fault.FaultCode != "" && fault.FaultString != "" {
+		fault.inheritNamespaces(envelope)
 		return &fault
 	}
 	return nil
 }
 
+// inheritNamespaces adds the namespace bindings of the enclosing Envelope and
+// Body elements to the fault, since detail entries may use their prefixes.
+func (f *Fault) inheritNamespaces(envelope *Envelope) {
+	scope := namespaceScope(namespaceScope(nil, envelope.Attrs), envelope.Body.Attrs)
+	maps.Copy(scope, f.namespaces)
+	f.namespaces = scope
+}
+
 func getUserAgent() string {
 	userAgent := "WayPlatformSOAPGo"
 	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
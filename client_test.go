@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -131,6 +132,79 @@ func TestClient_CallWithSOAPAction(t *testing.T) {
 	}
 }
 
+func TestClient_SOAP12(t *testing.T) {
+	t.Parallel()
+	const action = "http://example.com/TestAction"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedContentType := `application/soap+xml; action="http://example.com/TestAction"; charset=utf-8`
+		if got := r.Header.Get("Content-Type"); got != expectedContentType {
+			t.Errorf("Expected Content-Type %q, got %q", expectedContentType, got)
+		}
+		if got := r.Header.Get("SOAPAction"); got != "" {
+			t.Errorf("Expected no SOAPAction header for SOAP 1.2, got %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), Namespace12) {
+			t.Errorf("Expected SOAP 1.2 envelope namespace in request: %s", body)
+		}
+		respEnv, _ := NewEnvelope(WithVersion(Version12), WithBody([]byte(`<response>OK</response>`)))
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithSOAPVersion(Version12))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithVersion(Version12), WithBody([]byte(`<request>Test</request>`)))
+	respEnv, err := client.Call(context.Background(), action, reqEnv)
+	if err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if string(respEnv.Body.Content) != `<response>OK</response>` {
+		t.Errorf("Unexpected response body: %s", string(respEnv.Body.Content))
+	}
+}
+
+func TestClient_SOAP12Fault(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		faultEnv, _ := NewEnvelope(WithVersion(Version12), WithBody([]byte(`<soapenv:Fault>
+					<soapenv:Code><soapenv:Value>soapenv:Receiver</soapenv:Value></soapenv:Code>
+					<soapenv:Reason><soapenv:Text xml:lang="en">Database unavailable</soapenv:Text></soapenv:Reason>
+					<soapenv:Detail><retryAfter>30</retryAfter></soapenv:Detail>
+				</soapenv:Fault>`)))
+		respXML, _ := xml.Marshal(faultEnv)
+		w.Header().Set("Content-Type", "application/soap+xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithSOAPVersion(Version12))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithVersion(Version12), WithBody([]byte(`<request>Test</request>`)))
+	_, err = client.Call(context.Background(), "", reqEnv)
+	var soapErr *Error
+	if !errors.As(err, &soapErr) {
+		t.Fatalf("Expected unified Error, got: %v", err)
+	}
+	if soapErr.Fault == nil {
+		t.Fatal("Expected SOAP 1.2 fault to be decoded")
+	}
+	if soapErr.Fault.FaultCode != "soapenv:Receiver" {
+		t.Errorf("Expected fault code 'soapenv:Receiver', got: %s", soapErr.Fault.FaultCode)
+	}
+	if soapErr.Fault.FaultString != "Database unavailable" {
+		t.Errorf("Expected fault string 'Database unavailable', got: %s", soapErr.Fault.FaultString)
+	}
+	if soapErr.Fault.Detail == nil || string(soapErr.Fault.Detail.Content) != "<retryAfter>30</retryAfter>" {
+		t.Errorf("Unexpected fault detail: %+v", soapErr.Fault.Detail)
+	}
+}
+
 func TestClient_HTTPError(t *testing.T) {
 	t.Parallel()
 	// Create a test server that returns HTTP error
@@ -551,3 +625,33 @@ func TestClient_XMLDeclarationOption(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_HTTPClientSettings(t *testing.T) {
+	t.Parallel()
+	var sessions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		} else {
+			sessions = append(sessions, cookie.Value)
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+	}))
+	defer server.Close()
+	jar, _ := cookiejar.New(nil)
+	client, err := NewClient(WithEndpoint(server.URL), WithHTTPClient(&http.Client{Jar: jar}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	for range 2 {
+		if _, err := client.Call(context.Background(), "", reqEnv); err != nil {
+			t.Fatalf("Client.Call() error = %v", err)
+		}
+	}
+	if len(sessions) != 1 || sessions[0] != "abc" {
+		t.Errorf("Expected the cookie jar of the HTTP client to be used, got sessions: %v", sessions)
+	}
+}
@@ -2,10 +2,12 @@ package call
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/xml"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/way-platform/soap-go"
@@ -32,7 +34,15 @@ to include the full SOAP envelope in the response.`,
   soap call -e "http://example.com/service" -a "" -p envelope.xml --full-envelope
 
   # Include response envelope
-  soap call -e "http://example.com/service" -a "urn:GetWeather" -p request.xml --output-envelope`,
+  soap call -e "http://example.com/service" -a "urn:GetWeather" -p request.xml --output-envelope
+
+  # Mutual TLS with a PKCS#12 client certificate
+  soap call -e "https://example.com/service" -a "urn:GetWeather" -p request.xml \
+    --pkcs12 client.p12 --pkcs12-password "$P12_PASSWORD" --cacert partner-ca.pem
+
+  # OAuth2 client credentials
+  soap call -e "http://example.com/service" -a "urn:GetWeather" -p request.xml \
+    --token-url "https://auth.example.com/token" --client-id my-client --client-secret "$CLIENT_SECRET"`,
 	}
 
 	// Required flags
@@ -53,8 +63,42 @@ to include the full SOAP envelope in the response.`,
 
 	var debug bool
 	cmd.Flags().BoolVar(&debug, "debug", false, "dump HTTP requests and responses to stderr")
+	debugRedact := cmd.Flags().StringSlice("debug-redact", nil, "additional headers and XML elements to redact from debug output, e.g. wsse:Nonce")
+	debugMaxBody := cmd.Flags().Int("debug-max-body", 0, "truncate bodies in debug output beyond this many bytes (0 for no limit)")
+
+	// Authentication flags
+	bearerToken := cmd.Flags().String("bearer-token", "", "bearer token for the Authorization header")
+	tokenURL := cmd.Flags().String("token-url", "", "OAuth2 token endpoint URL, to get bearer tokens with the client credentials grant")
+	clientID := cmd.Flags().String("client-id", "", "OAuth2 client ID")
+	clientSecret := cmd.Flags().String("client-secret", "", "OAuth2 client secret")
+	scopes := cmd.Flags().StringSlice("scope", nil, "OAuth2 scopes to request")
+	cmd.MarkFlagsMutuallyExclusive("bearer-token", "token-url")
+	cmd.MarkFlagsRequiredTogether("token-url", "client-id")
+
+	// TLS flags
+	certFile := cmd.Flags().String("cert", "", "PEM client certificate file for mutual TLS")
+	keyFile := cmd.Flags().String("key", "", "PEM private key file of the client certificate")
+	pkcs12File := cmd.Flags().String("pkcs12", "", "PKCS#12 (.p12 or .pfx) client certificate file for mutual TLS")
+	pkcs12Password := cmd.Flags().String("pkcs12-password", "", "password of the PKCS#12 file")
+	caFile := cmd.Flags().String("cacert", "", "PEM file of the certificate authorities trusted to verify the server")
+	pins := cmd.Flags().StringSlice("pin", nil, "SHA-256 fingerprints of accepted server certificates, in hex")
+	tlsMinVersion := cmd.Flags().String("tls-min-version", "", "minimum TLS version: 1.2 or 1.3")
+	cmd.MarkFlagsRequiredTogether("cert", "key")
+	cmd.MarkFlagsMutuallyExclusive("cert", "pkcs12")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var tokenSource soap.TokenSource
+		switch {
+		case *bearerToken != "":
+			tokenSource = soap.StaticToken(*bearerToken)
+		case *tokenURL != "":
+			tokenSource = &soap.ClientCredentials{
+				TokenURL:     *tokenURL,
+				ClientID:     *clientID,
+				ClientSecret: *clientSecret,
+				Scopes:       *scopes,
+			}
+		}
 		return run(config{
 			endpoint:       *endpoint,
 			action:         *action,
@@ -64,10 +108,22 @@ to include the full SOAP envelope in the response.`,
 			outputFile:     *output,
 			httpClient: &http.Client{
 				Transport: &soap.DebugTransport{
-					Enabled: &debug,
-					Next:    http.DefaultTransport,
+					Enabled:      &debug,
+					Next:         http.DefaultTransport,
+					Redact:       append(slices.Clone(soap.DefaultRedactions), *debugRedact...),
+					MaxBodyBytes: *debugMaxBody,
 				},
 			},
+			tokenSource: tokenSource,
+			tls: tlsConfig{
+				certFile:       *certFile,
+				keyFile:        *keyFile,
+				pkcs12File:     *pkcs12File,
+				pkcs12Password: *pkcs12Password,
+				caFile:         *caFile,
+				pins:           *pins,
+				minVersion:     *tlsMinVersion,
+			},
 		})
 	}
 
@@ -82,6 +138,45 @@ type config struct {
 	outputEnvelope bool
 	outputFile     string
 	httpClient     *http.Client
+	tokenSource    soap.TokenSource
+	tls            tlsConfig
+}
+
+type tlsConfig struct {
+	certFile       string
+	keyFile        string
+	pkcs12File     string
+	pkcs12Password string
+	caFile         string
+	pins           []string
+	minVersion     string
+}
+
+// options returns the client options of the TLS configuration.
+func (cfg tlsConfig) options() ([]soap.ClientOption, error) {
+	var opts []soap.ClientOption
+	if cfg.certFile != "" {
+		opts = append(opts, soap.WithClientCertificateFiles(cfg.certFile, cfg.keyFile))
+	}
+	if cfg.pkcs12File != "" {
+		opts = append(opts, soap.WithPKCS12File(cfg.pkcs12File, cfg.pkcs12Password))
+	}
+	if cfg.caFile != "" {
+		opts = append(opts, soap.WithRootCAFile(cfg.caFile))
+	}
+	if len(cfg.pins) > 0 {
+		opts = append(opts, soap.WithPinnedCertificates(cfg.pins...))
+	}
+	switch cfg.minVersion {
+	case "":
+	case "1.2":
+		opts = append(opts, soap.WithMinTLSVersion(tls.VersionTLS12))
+	case "1.3":
+		opts = append(opts, soap.WithMinTLSVersion(tls.VersionTLS13))
+	default:
+		return nil, fmt.Errorf("invalid TLS version %q, expected 1.2 or 1.3", cfg.minVersion)
+	}
+	return opts, nil
 }
 
 func run(cfg config) error {
@@ -101,6 +196,14 @@ func run(cfg config) error {
 	if cfg.httpClient != nil {
 		opts = append(opts, soap.WithHTTPClient(cfg.httpClient))
 	}
+	if cfg.tokenSource != nil {
+		opts = append(opts, soap.WithTokenSource(cfg.tokenSource))
+	}
+	tlsOpts, err := cfg.tls.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, tlsOpts...)
 	client, err := soap.NewClient(opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create SOAP client: %w", err)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/way-platform/soap-go/internal/soapgen"
@@ -23,28 +25,47 @@ func NewCommand() *cobra.Command {
 	_ = cmd.MarkFlagRequired("dir")
 	packageName := cmd.Flags().StringP("package", "p", "", "Go package name (required)")
 	generateClient := cmd.Flags().Bool("client", false, "generate SOAP client code")
+	generateServer := cmd.Flags().Bool("server", false, "generate SOAP service interface and server handler code")
+	mtomOperations := cmd.Flags().StringSlice("mtom", nil, "operations whose requests are sent as MTOM/XOP packages")
+	idempotentOperations := cmd.Flags().StringSlice("idempotent", nil, "operations that are safe to retry on server errors and timeouts")
+	rateLimits := cmd.Flags().StringToString("rate-limit", nil, "client-side rate limits of operations, as Operation=perSecond[:burst]")
+	base64BinaryType := cmd.Flags().Bool("base64-binary", false, "map xsd:base64Binary to soap.Base64Binary instead of []byte (implied by --mtom)")
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		return run(config{
-			inputFile:      *inputFile,
-			outputDir:      *outputDir,
-			packageName:    *packageName,
-			generateClient: *generateClient,
+			inputFile:            *inputFile,
+			outputDir:            *outputDir,
+			packageName:          *packageName,
+			generateClient:       *generateClient,
+			generateServer:       *generateServer,
+			mtomOperations:       *mtomOperations,
+			idempotentOperations: *idempotentOperations,
+			rateLimits:           *rateLimits,
+			base64BinaryType:     *base64BinaryType,
 		})
 	}
 	return cmd
 }
 
 type config struct {
-	inputFile      string
-	outputDir      string
-	packageName    string
-	generateClient bool
+	inputFile            string
+	outputDir            string
+	packageName          string
+	generateClient       bool
+	generateServer       bool
+	mtomOperations       []string
+	idempotentOperations []string
+	rateLimits           map[string]string
+	base64BinaryType     bool
 }
 
 func run(cfg config) error {
 	if cfg.packageName == "" {
 		cfg.packageName = filepath.Base(cfg.outputDir)
 	}
+	rateLimits, err := parseRateLimits(cfg.rateLimits)
+	if err != nil {
+		return err
+	}
 	// Parse the WSDL file
 	defs, err := wsdl.ParseFromFile(cfg.inputFile)
 	if err != nil {
@@ -58,8 +79,13 @@ func run(cfg config) error {
 
 	// Create generator with configuration
 	generator := soapgen.NewGenerator(defs, soapgen.Config{
-		PackageName:    cfg.packageName,
-		GenerateClient: cfg.generateClient,
+		PackageName:          cfg.packageName,
+		GenerateClient:       cfg.generateClient,
+		GenerateServer:       cfg.generateServer,
+		MTOMOperations:       cfg.mtomOperations,
+		IdempotentOperations: cfg.idempotentOperations,
+		RateLimits:           rateLimits,
+		Base64BinaryType:     cfg.base64BinaryType,
 	})
 
 	// Generate the code
@@ -85,3 +111,26 @@ func run(cfg config) error {
 
 	return nil
 }
+
+// parseRateLimits parses rate limits of operations given as perSecond[:burst].
+func parseRateLimits(values map[string]string) (map[string]soapgen.RateLimit, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	rateLimits := make(map[string]soapgen.RateLimit, len(values))
+	for operation, value := range values {
+		perSecond, burst, hasBurst := strings.Cut(value, ":")
+		var limit soapgen.RateLimit
+		var err error
+		if limit.PerSecond, err = strconv.ParseFloat(perSecond, 64); err != nil || limit.PerSecond <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q for operation %s", value, operation)
+		}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst < 1 {
+				return nil, fmt.Errorf("invalid rate limit burst %q for operation %s", value, operation)
+			}
+		}
+		rateLimits[operation] = limit
+	}
+	return rateLimits, nil
+}
@@ -2,18 +2,37 @@ package soap
 
 import (
 	"bytes"
+	"encoding/xml"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"strings"
 )
 
+// DefaultRedactions are the HTTP headers and XML elements redacted by a
+// [DebugTransport] without explicit redactions.
+var DefaultRedactions = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Password",
+}
+
+// redactedValue replaces redacted header values and element content.
+const redactedValue = "[REDACTED]"
+
 // DebugTransport is an [http.RoundTripper] that dumps requests and responses
 // to stderr. When Enabled is nil or points to false, requests pass through
 // to Next unchanged.
 //
-// Standalone CLIs can wire a --debug flag to Enabled:
+// XML bodies are indented, and sensitive header values and element content
+// are redacted per Redact. Of multipart/related messages, only the XML root
+// part is dumped. Bodies that are not XML are omitted, since they cannot be
+// redacted. Standalone CLIs can wire a --debug flag to Enabled:
 //
 //	var debug bool
 //	t := &soap.DebugTransport{Enabled: &debug, Next: http.DefaultTransport}
@@ -25,6 +44,23 @@ type DebugTransport struct {
 	Enabled *bool
 	// Next is the underlying transport. If nil, [http.DefaultTransport] is used.
 	Next http.RoundTripper
+	// Writer receives the dumps. If nil, [os.Stderr] is used.
+	Writer io.Writer
+	// Logger, if set, receives the dumps as debug level records instead of Writer.
+	Logger *slog.Logger
+	// Redact lists the HTTP headers and XML elements whose values are replaced
+	// by [REDACTED]. Entries match header names case-insensitively, and
+	// elements by name or path: "Password" matches elements with that local
+	// name, "wsse:Password" also requires the prefix used in the message,
+	// "Login/Password" matches a Password child of a Login element, and
+	// "/Envelope/Header/Token" matches from the root element.
+	// If nil, [DefaultRedactions] is used.
+	Redact []string
+	// MaxBodyBytes truncates dumped bodies longer than this many bytes.
+	// Zero means no limit.
+	MaxBodyBytes int
+	// DisableIndent dumps XML bodies without indentation.
+	DisableIndent bool
 }
 
 func (t *DebugTransport) next() http.RoundTripper {
@@ -34,28 +70,271 @@ func (t *DebugTransport) next() http.RoundTripper {
 	return http.DefaultTransport
 }
 
+func (t *DebugTransport) redactions() []string {
+	if t.Redact != nil {
+		return t.Redact
+	}
+	return DefaultRedactions
+}
+
 // RoundTrip implements [http.RoundTripper].
 func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if t.Enabled == nil || !*t.Enabled {
 		return t.next().RoundTrip(req)
 	}
-	requestDump, err := httputil.DumpRequestOut(req, true)
+	var requestBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for debug: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	dumpReq := req.Clone(req.Context())
+	dumpReq.Header = t.redactHeader(req.Header)
+	requestDump, err := httputil.DumpRequestOut(dumpReq, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dump request for debug: %w", err)
 	}
-	prettyPrintDump(os.Stderr, requestDump, "> ")
+	t.write(req, "SOAP request", t.appendBody(requestDump, req.Header.Get("Content-Type"), requestBody), "> ")
 	resp, err := t.next().RoundTrip(req)
 	if err != nil {
 		return nil, err
 	}
-	responseDump, err := httputil.DumpResponse(resp, true)
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for debug: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	dumpResp := *resp
+	dumpResp.Header = t.redactHeader(resp.Header)
+	dumpResp.Body = nil
+	responseDump, err := httputil.DumpResponse(&dumpResp, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dump response for debug: %w", err)
 	}
-	prettyPrintDump(os.Stderr, responseDump, "< ")
+	t.write(req, "SOAP response", t.appendBody(responseDump, resp.Header.Get("Content-Type"), responseBody), "< ")
 	return resp, nil
 }
 
+// write sends a dump to the logger or the writer.
+func (t *DebugTransport) write(req *http.Request, message string, dump []byte, prefix string) {
+	if t.Logger != nil {
+		t.Logger.LogAttrs(req.Context(), slog.LevelDebug, message, slog.String("http.dump", string(dump)))
+		return
+	}
+	w := t.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+	prettyPrintDump(w, dump, prefix)
+}
+
+// redactHeader returns a copy of the header with redacted values.
+func (t *DebugTransport) redactHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for name := range result {
+		for _, redaction := range t.redactions() {
+			if strings.EqualFold(name, redaction) {
+				result[name] = []string{redactedValue}
+			}
+		}
+	}
+	return result
+}
+
+// appendBody appends the formatted body to a header dump.
+func (t *DebugTransport) appendBody(dump []byte, contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return dump
+	}
+	body = t.formatBody(contentType, body)
+	if t.MaxBodyBytes > 0 && len(body) > t.MaxBodyBytes {
+		body = fmt.Appendf(body[:t.MaxBodyBytes:t.MaxBodyBytes], "\n... (%d bytes truncated)", len(body)-t.MaxBodyBytes)
+	}
+	return append(append(dump, body...), '\n')
+}
+
+// formatBody formats and redacts a body. The root part of a multipart/related
+// message is formatted as XML, and its other parts are summarized.
+func (t *DebugTransport) formatBody(contentType string, body []byte) []byte {
+	if !isMultipartRelated(contentType) {
+		return t.formatXMLBody(body)
+	}
+	root, parts, err := decodeMultipartRelated(contentType, body)
+	if err != nil {
+		return omittedBody(body, err)
+	}
+	formatted := t.formatXMLBody(root.data)
+	for _, part := range parts {
+		formatted = fmt.Appendf(formatted, "\n[attachment <%s> %s: %d bytes]",
+			part.contentID(), part.header.Get("Content-Type"), len(part.data))
+	}
+	return formatted
+}
+
+// formatXMLBody formats and redacts an XML body. Bodies that cannot be parsed
+// are omitted, since redacted elements cannot be found in them.
+func (t *DebugTransport) formatXMLBody(body []byte) []byte {
+	formatted, err := formatDebugXML(body, t.redactions(), !t.DisableIndent)
+	if err != nil {
+		return omittedBody(body, err)
+	}
+	return formatted
+}
+
+// omittedBody is the placeholder of a body that cannot be redacted.
+func omittedBody(body []byte, err error) []byte {
+	return fmt.Appendf(nil, "[%d bytes omitted: cannot redact body: %v]", len(body), err)
+}
+
+// formatDebugXML re-serializes an XML document with the content of redacted
+// elements replaced, optionally indented. Prefixes are kept as written.
+func formatDebugXML(data []byte, redactions []string, indent bool) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var b bytes.Buffer
+	var path []xml.Name
+	open := false // the last token written is a start tag
+	root := false // the root element was read
+	newline := func() {
+		if indent && b.Len() > 0 {
+			b.WriteByte('\n')
+			b.WriteString(strings.Repeat("  ", len(path)))
+		}
+	}
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(path) == 0 && root {
+				return nil, fmt.Errorf("multiple root elements")
+			}
+			root = true
+			newline()
+			b.WriteString("<" + rawName(t.Name))
+			for _, attr := range t.Attr {
+				b.WriteString(" " + rawName(attr.Name) + `="`)
+				_ = xml.EscapeText(&b, []byte(attr.Value))
+				b.WriteString(`"`)
+			}
+			b.WriteString(">")
+			path = append(path, t.Name)
+			open = true
+			if matchesRedaction(path, redactions) {
+				if err := skipRaw(d); err != nil {
+					return nil, err
+				}
+				path = path[:len(path)-1]
+				b.WriteString(redactedValue + "</" + rawName(t.Name) + ">")
+				open = false
+			}
+		case xml.EndElement:
+			if len(path) == 0 {
+				return nil, fmt.Errorf("unexpected end element %s", rawName(t.Name))
+			}
+			path = path[:len(path)-1]
+			if !open {
+				newline()
+			}
+			b.WriteString("</" + rawName(t.Name) + ">")
+			open = false
+		case xml.CharData:
+			text := []byte(t)
+			if len(path) == 0 && len(bytes.TrimSpace(text)) > 0 {
+				return nil, fmt.Errorf("text outside of the root element")
+			}
+			if indent {
+				if text = bytes.TrimSpace(text); len(text) == 0 {
+					continue
+				}
+				if !open {
+					newline()
+				}
+			}
+			_ = xml.EscapeText(&b, text)
+		case xml.Comment:
+			newline()
+			b.WriteString("<!--" + string(t) + "-->")
+			open = false
+		case xml.ProcInst:
+			newline()
+			b.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+			open = false
+		case xml.Directive:
+			newline()
+			b.WriteString("<!" + string(t) + ">")
+			open = false
+		}
+	}
+	if !root || len(path) > 0 {
+		return nil, fmt.Errorf("incomplete XML document")
+	}
+	return b.Bytes(), nil
+}
+
+// skipRaw skips the remaining tokens of the current element, using RawToken
+// like its caller so that the decoder's element checks stay consistent.
+func skipRaw(d *xml.Decoder) error {
+	for depth := 1; depth > 0; {
+		token, err := d.RawToken()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
+
+// rawName returns a name as written in the document, with its prefix.
+func rawName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// matchesRedaction reports whether an element path, as raw names from the
+// root element, matches any of the redactions.
+func matchesRedaction(path []xml.Name, redactions []string) bool {
+	for _, redaction := range redactions {
+		absolute := strings.HasPrefix(redaction, "/") && !strings.HasPrefix(redaction, "//")
+		steps := strings.Split(strings.TrimLeft(redaction, "/"), "/")
+		if len(steps) > len(path) || (absolute && len(steps) != len(path)) {
+			continue
+		}
+		tail := path[len(path)-len(steps):]
+		matched := true
+		for i, step := range steps {
+			prefix, local, ok := strings.Cut(step, ":")
+			if !ok {
+				prefix, local = "", step
+			}
+			if (local != "*" && local != tail[i].Local) || (ok && prefix != tail[i].Space) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
 func prettyPrintDump(w io.Writer, dump []byte, prefix string) {
 	var output bytes.Buffer
 	output.Grow(len(dump) * 2)
@@ -0,0 +1,171 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestDebugTransport(t *testing.T) {
+	t.Parallel()
+	server := staticServer(t, http.StatusOK, `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">`+
+		`<soapenv:Body><ns:LoginResponse xmlns:ns="urn:auth"><ns:SessionToken>s3cr3t-session</ns:SessionToken>`+
+		`<ns:Message>`+strings.Repeat("x", 2000)+`</ns:Message></ns:LoginResponse></soapenv:Body></soapenv:Envelope>`)
+	t.Cleanup(server.Close)
+	enabled := true
+	var out bytes.Buffer
+	transport := &DebugTransport{
+		Enabled:      &enabled,
+		Writer:       &out,
+		Redact:       append([]string{"X-Session", "ns:SessionToken"}, DefaultRedactions...),
+		MaxBodyBytes: 1200,
+	}
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithUsernameToken("alice", "hunter2", PasswordText),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	req, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+	if _, err := client.Call(context.Background(), "urn:login", req, WithInterceptor(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Session", "s3cr3t-header")
+			r.Header.Set("Authorization", "Basic s3cr3t")
+			return next.RoundTrip(r)
+		})
+	})); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	dump := out.String()
+	for _, secret := range []string{"hunter2", "s3cr3t"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Expected %q to be redacted:\n%s", secret, dump)
+		}
+	}
+	for _, expected := range []string{
+		"> X-Session: [REDACTED]",
+		"> Authorization: [REDACTED]",
+		"[REDACTED]</wsse:Password>",
+		"<ns:SessionToken>[REDACTED]</ns:SessionToken>",
+		"<   <soapenv:Body>",
+		"bytes truncated)",
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("Expected dump to contain %q:\n%s", expected, dump)
+		}
+	}
+}
+
+func TestDebugTransport_FailsClosed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		mtom        bool
+		want        string
+	}{
+		{name: "MTOM request", contentType: "text/xml", body: "<ok/>", mtom: true, want: "[REDACTED]</wsse:Password>"},
+		{name: "HTML response", contentType: "text/html", body: "<html><p>hunter2<br></p></html>", want: "bytes omitted: cannot redact body"},
+		{name: "missing content type", body: "password=hunter2", want: "bytes omitted: cannot redact body"},
+		{name: "truncated XML", contentType: "text/xml", body: "<a><Password>hunter2", want: "bytes omitted: cannot redact body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			enabled := true
+			var out bytes.Buffer
+			transport := &DebugTransport{
+				Enabled: &enabled,
+				Writer:  &out,
+				Next: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+					header := http.Header{}
+					if tt.contentType != "" {
+						header.Set("Content-Type", tt.contentType)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     header,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+						Request:    r,
+					}, nil
+				}),
+			}
+			client, err := NewClient(
+				WithEndpoint("http://example.com"),
+				WithHTTPClient(&http.Client{Transport: transport}),
+				WithUsernameToken("alice", "hunter2", PasswordText),
+				WithMTOM(tt.mtom),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			req, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+			_, _ = client.Call(context.Background(), "urn:echo", req)
+			dump := out.String()
+			if strings.Contains(dump, "hunter2") {
+				t.Errorf("Expected the password to be redacted:\n%s", dump)
+			}
+			if !strings.Contains(dump, tt.want) {
+				t.Errorf("Expected dump to contain %q:\n%s", tt.want, dump)
+			}
+		})
+	}
+}
+
+func TestDebugTransport_Logger(t *testing.T) {
+	t.Parallel()
+	server := staticServer(t, http.StatusOK, `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`)
+	t.Cleanup(server.Close)
+	enabled := true
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, _ := NewClient(
+		WithEndpoint(server.URL),
+		WithHTTPClient(&http.Client{Transport: &DebugTransport{Enabled: &enabled, Logger: logger}}),
+	)
+	req, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+	if _, err := client.Call(context.Background(), "urn:echo", req); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `msg="SOAP request"`) || !strings.Contains(out.String(), `msg="SOAP response"`) {
+		t.Errorf("Expected request and response records, got:\n%s", out.String())
+	}
+}
+
+func TestMatchesRedaction(t *testing.T) {
+	t.Parallel()
+	path := []xml.Name{{Space: "soapenv", Local: "Envelope"}, {Space: "soapenv", Local: "Header"}, {Space: "wsse", Local: "Password"}}
+	tests := []struct {
+		redaction string
+		want      bool
+	}{
+		{redaction: "Password", want: true},
+		{redaction: "wsse:Password", want: true},
+		{redaction: "sec:Password", want: false},
+		{redaction: "Header/Password", want: true},
+		{redaction: "//Header/*", want: true},
+		{redaction: "Body/Password", want: false},
+		{redaction: "/Envelope/Header/Password", want: true},
+		{redaction: "/Header/Password", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchesRedaction(path, []string{tt.redaction}); got != tt.want {
+			t.Errorf("matchesRedaction(%q) = %v, want %v", tt.redaction, got, tt.want)
+		}
+	}
+}
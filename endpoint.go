@@ -0,0 +1,239 @@
+package soap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointStrategy selects the order in which the endpoints of an
+// [EndpointPool] are tried.
+type EndpointStrategy int
+
+const (
+	// Failover sends requests to the first healthy endpoint, and to the
+	// following ones only when it fails.
+	Failover EndpointStrategy = iota
+	// RoundRobin spreads requests across the healthy endpoints in turn.
+	RoundRobin
+)
+
+// EndpointPool is a set of equivalent endpoints for a service, such as the
+// primary and disaster recovery addresses of the ports of a WSDL service.
+//
+// An attempt that fails to connect, or with HTTP 503, is sent to the next
+// endpoint. Attempts that fail with other network errors or HTTP 502 or 504
+// may have been processed, and are only sent to the next endpoint for
+// idempotent calls, see [WithIdempotent]. These failures all count against
+// the health of the endpoint. An endpoint is ejected from the pool after a number of
+// consecutive failures, and tried again after a cooldown. When all endpoints
+// are ejected, all are tried. A pool tracks health across calls; share it
+// between calls by passing it to [WithEndpointPool].
+type EndpointPool struct {
+	strategy    EndpointStrategy
+	maxFailures int
+	cooldown    time.Duration
+	endpoints   []*endpointState
+	next        atomic.Uint64
+}
+
+// endpointState is the health of an endpoint of a pool.
+type endpointState struct {
+	url          *url.URL
+	mu           sync.Mutex
+	failures     int
+	ejectedUntil time.Time
+}
+
+// EndpointPoolOption configures an EndpointPool using the functional options pattern.
+type EndpointPoolOption func(*EndpointPool)
+
+// WithStrategy sets the strategy of the pool. Defaults to [Failover].
+func WithStrategy(strategy EndpointStrategy) EndpointPoolOption {
+	return func(p *EndpointPool) {
+		p.strategy = strategy
+	}
+}
+
+// WithEjection sets the number of consecutive failures after which an
+// endpoint is ejected, and for how long. Defaults to 3 failures and 30s.
+func WithEjection(consecutiveFailures int, cooldown time.Duration) EndpointPoolOption {
+	return func(p *EndpointPool) {
+		p.maxFailures = consecutiveFailures
+		p.cooldown = cooldown
+	}
+}
+
+// NewEndpointPool creates a pool of endpoint URLs, in order of preference.
+// Returns an error if there is no endpoint or an endpoint is not a valid URL.
+func NewEndpointPool(endpoints []string, opts ...EndpointPoolOption) (*EndpointPool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("endpoint pool requires at least one endpoint")
+	}
+	pool := &EndpointPool{strategy: Failover, maxFailures: 3, cooldown: 30 * time.Second}
+	for _, opt := range opts {
+		opt(pool)
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("missing scheme or host")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+		pool.endpoints = append(pool.endpoints, &endpointState{url: u})
+	}
+	return pool, nil
+}
+
+// WithEndpointPool sends requests to the endpoints of a pool. It replaces the
+// endpoint set with [WithEndpoint]; the first endpoint of the pool is used
+// where a single address is needed, such as the WS-Addressing To header. A nil
+// pool fails the configuration.
+func WithEndpointPool(pool *EndpointPool) ClientOption {
+	if pool == nil || len(pool.endpoints) == 0 {
+		return withError(errors.New("invalid endpoint pool: no endpoints"))
+	}
+	return func(c *clientConfig) {
+		c.endpointPool = pool
+		c.endpoint = pool.endpoints[0].url.String()
+	}
+}
+
+// WithEndpoints sends requests to the endpoints with the [Failover] strategy.
+// It is a shorthand for [WithEndpointPool] with a new pool.
+func WithEndpoints(endpoints ...string) ClientOption {
+	pool, err := NewEndpointPool(endpoints)
+	if err != nil {
+		return withError(err)
+	}
+	return WithEndpointPool(pool)
+}
+
+// order returns the endpoints to try for an attempt, healthy ones first.
+func (p *EndpointPool) order() []*endpointState {
+	start := 0
+	if p.strategy == RoundRobin {
+		start = int(p.next.Add(1)-1) % len(p.endpoints)
+	}
+	now := time.Now()
+	var healthy, ejected []*endpointState
+	for i := range p.endpoints {
+		endpoint := p.endpoints[(start+i)%len(p.endpoints)]
+		if endpoint.isEjected(now) {
+			ejected = append(ejected, endpoint)
+		} else {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	return append(healthy, ejected...)
+}
+
+func (e *endpointState) isEjected(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.Before(e.ejectedUntil)
+}
+
+// record updates the health of an endpoint with the outcome of an attempt.
+func (p *EndpointPool) record(e *endpointState, failed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !failed {
+		e.failures = 0
+		return
+	}
+	e.failures++
+	if p.maxFailures > 0 && e.failures >= p.maxFailures {
+		e.ejectedUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+// isEndpointFailure reports whether an attempt failed because of the endpoint
+// rather than the request, for the health of the endpoint.
+func isEndpointFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// canFailover reports whether a failed attempt may be sent to another
+// endpoint. Requests that may have been processed, such as after a network
+// error once the request was sent or a gateway error, are only sent again
+// if they are idempotent. Requests rejected by an open circuit were never
+// sent, and always fail over.
+func canFailover(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, ErrCircuitOpen) || isConnectErr(err) || isIdempotent(req)
+	}
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+	return false
+}
+
+// isConnectErr reports whether an error occurred while connecting to the
+// endpoint, before the request was sent.
+func isConnectErr(err error) bool {
+	var opErr *net.OpError
+	return isDNSErr(err) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// endpointTransport sends each attempt to the endpoints of a pool in turn.
+type endpointTransport struct {
+	pool *EndpointPool
+	next http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoints := t.pool.order()
+	// Without GetBody the request body cannot be sent to another endpoint
+	canResend := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	var resp *http.Response
+	var err error
+	for i, endpoint := range endpoints {
+		attempt := req.Clone(req.Context())
+		u := *endpoint.url
+		attempt.URL = &u
+		attempt.Host = ""
+		if i > 0 && req.GetBody != nil {
+			if attempt.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("failed to reset request body: %w", err)
+			}
+		}
+		resp, err = t.next.RoundTrip(attempt)
+		if err != nil && req.Context().Err() != nil {
+			// Canceled calls say nothing about the health of the endpoint
+			break
+		}
+		failed := isEndpointFailure(resp, err)
+		if !errors.Is(err, ErrCircuitOpen) {
+			// Requests rejected by an open circuit never reached the endpoint
+			t.pool.record(endpoint, failed)
+		}
+		if !failed || !canResend || !canFailover(req, resp, err) || i == len(endpoints)-1 {
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}
+	return resp, err
+}
@@ -0,0 +1,194 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCountingServer starts a server answering with the status code, or an
+// empty SOAP response for HTTP 200, and counting its requests.
+func newCountingServer(t *testing.T, statusCode int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(statusCode)
+		if statusCode == http.StatusOK {
+			_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestEndpointPool_Failover(t *testing.T) {
+	t.Parallel()
+	primary, primaryRequests := newCountingServer(t, http.StatusServiceUnavailable)
+	secondary, secondaryRequests := newCountingServer(t, http.StatusOK)
+	pool, err := NewEndpointPool([]string{primary.URL, secondary.URL}, WithEjection(2, time.Minute))
+	if err != nil {
+		t.Fatalf("NewEndpointPool() error = %v", err)
+	}
+	client, err := NewClient(WithEndpointPool(pool), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	for i := range 3 {
+		if _, err := client.Call(context.Background(), "urn:test", reqEnv); err != nil {
+			t.Fatalf("Call %d failed: %v", i+1, err)
+		}
+	}
+	// The primary is ejected after its second consecutive failure
+	if got := primaryRequests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests to the primary endpoint, got %d", got)
+	}
+	if got := secondaryRequests.Load(); got != 3 {
+		t.Errorf("Expected 3 requests to the secondary endpoint, got %d", got)
+	}
+}
+
+func TestEndpointPool_RoundRobin(t *testing.T) {
+	t.Parallel()
+	first, firstRequests := newCountingServer(t, http.StatusOK)
+	second, secondRequests := newCountingServer(t, http.StatusOK)
+	pool, err := NewEndpointPool([]string{first.URL, second.URL}, WithStrategy(RoundRobin))
+	if err != nil {
+		t.Fatalf("NewEndpointPool() error = %v", err)
+	}
+	client, err := NewClient(WithEndpointPool(pool))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	for i := range 4 {
+		if _, err := client.Call(context.Background(), "urn:test", reqEnv); err != nil {
+			t.Fatalf("Call %d failed: %v", i+1, err)
+		}
+	}
+	if firstRequests.Load() != 2 || secondRequests.Load() != 2 {
+		t.Errorf("Expected 2 requests per endpoint, got %d and %d", firstRequests.Load(), secondRequests.Load())
+	}
+}
+
+func TestEndpointPool_AllFailing(t *testing.T) {
+	t.Parallel()
+	first, _ := newCountingServer(t, http.StatusBadGateway)
+	second, _ := newCountingServer(t, http.StatusGatewayTimeout)
+	client, err := NewClient(WithEndpoints(first.URL, second.URL), WithMaxRetries(0), WithIdempotent(true))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	_, err = client.Call(context.Background(), "urn:test", reqEnv)
+	var soapErr *Error
+	if !errors.As(err, &soapErr) || soapErr.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("Expected the error of the last endpoint, got: %v", err)
+	}
+}
+
+func TestEndpointPool_NonIdempotent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		primaryStatus   int
+		primaryDown     bool
+		wantFailover    bool
+		wantPrimaryHits int32
+	}{
+		{name: "connection refused", primaryDown: true, wantFailover: true},
+		{name: "service unavailable", primaryStatus: http.StatusServiceUnavailable, wantFailover: true, wantPrimaryHits: 1},
+		{name: "bad gateway", primaryStatus: http.StatusBadGateway, wantPrimaryHits: 1},
+		{name: "gateway timeout", primaryStatus: http.StatusGatewayTimeout, wantPrimaryHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			primary, primaryRequests := newCountingServer(t, tt.primaryStatus)
+			if tt.primaryDown {
+				primary.Close()
+			}
+			secondary, secondaryRequests := newCountingServer(t, http.StatusOK)
+			client, err := NewClient(WithEndpoints(primary.URL, secondary.URL), WithMaxRetries(0))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+			_, err = client.Call(context.Background(), "urn:test", reqEnv)
+			if tt.wantFailover != (err == nil) {
+				t.Errorf("Expected failover %v, got error: %v", tt.wantFailover, err)
+			}
+			if got := primaryRequests.Load(); got != tt.wantPrimaryHits {
+				t.Errorf("Expected %d requests to the primary, got: %d", tt.wantPrimaryHits, got)
+			}
+			if got, want := secondaryRequests.Load(), map[bool]int32{true: 1}[tt.wantFailover]; got != want {
+				t.Errorf("Expected %d requests to the secondary, got: %d", want, got)
+			}
+		})
+	}
+}
+
+func TestWithEndpoints_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, endpoints := range [][]string{nil, {""}, {"not a url"}, {"http://example.com", "://bad"}} {
+		if _, err := NewClient(WithEndpoints(endpoints...)); err == nil {
+			t.Errorf("Expected an error for endpoints %q", endpoints)
+		}
+	}
+}
+
+func TestWithEndpointPool_Nil(t *testing.T) {
+	t.Parallel()
+	if _, err := NewClient(WithEndpointPool(nil)); err == nil {
+		t.Error("Expected an error for a nil endpoint pool")
+	}
+}
+
+func TestEndpointPool_CircuitPerEndpoint(t *testing.T) {
+	t.Parallel()
+	primary, primaryRequests := newCountingServer(t, http.StatusBadGateway)
+	secondary, secondaryRequests := newCountingServer(t, http.StatusOK)
+	pool, err := NewEndpointPool([]string{primary.URL, secondary.URL}, WithEjection(2, time.Minute))
+	if err != nil {
+		t.Fatalf("NewEndpointPool() error = %v", err)
+	}
+	breaker := NewCircuitBreaker(
+		WithCircuitKey(CircuitPerEndpoint),
+		WithCircuitThresholds(CircuitThresholds{HTTPErrors: 1}),
+	)
+	client, err := NewClient(WithEndpointPool(pool), WithCircuitBreaker(breaker), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	// The first call is not idempotent, so the bad gateway opens the circuit
+	// of the primary without failing over
+	if _, err := client.Call(context.Background(), "urn:test", reqEnv); err == nil {
+		t.Fatal("Expected the first call to fail")
+	}
+	if got := breaker.State(primary.URL); got != CircuitOpen {
+		t.Fatalf("Expected the circuit of the primary to be open, got %v", got)
+	}
+	// The open circuit rejects the next calls before sending them, so they
+	// fail over to the secondary without counting against the primary
+	for i := range 3 {
+		if _, err := client.Call(context.Background(), "urn:test", reqEnv); err != nil {
+			t.Fatalf("Call %d failed: %v", i+2, err)
+		}
+	}
+	if got := primaryRequests.Load(); got != 1 {
+		t.Errorf("Expected 1 request to the primary endpoint, got %d", got)
+	}
+	if got := secondaryRequests.Load(); got != 3 {
+		t.Errorf("Expected 3 requests to the secondary endpoint, got %d", got)
+	}
+	if pool.endpoints[0].isEjected(time.Now()) {
+		t.Error("Expected circuit rejections not to eject the primary endpoint")
+	}
+}
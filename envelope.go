@@ -3,12 +3,42 @@ package soap
 import (
 	"encoding/xml"
 	"fmt"
+	"slices"
 	"strings"
 )
 
 // Namespace is the standard SOAP 1.1 envelope namespace
 const Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
 
+// Namespace12 is the standard SOAP 1.2 envelope namespace
+const Namespace12 = "http://www.w3.org/2003/05/soap-envelope"
+
+// Version identifies a SOAP protocol version.
+type Version int
+
+const (
+	// Version11 is SOAP 1.1, the default.
+	Version11 Version = iota + 1
+	// Version12 is SOAP 1.2.
+	Version12
+)
+
+// Namespace returns the envelope namespace for the SOAP version.
+func (v Version) Namespace() string {
+	if v == Version12 {
+		return Namespace12
+	}
+	return Namespace
+}
+
+// String returns the SOAP version number, e.g. "1.1".
+func (v Version) String() string {
+	if v == Version12 {
+		return "1.2"
+	}
+	return "1.1"
+}
+
 // Envelope represents a SOAP envelope with flexible namespace support.
 // It can handle any namespace prefix and URI, making it compatible with various SOAP implementations.
 // The XMLName field determines the actual element name and namespace used in marshaling/unmarshaling.
@@ -26,6 +56,42 @@ type Envelope struct {
 
 	// Additional attributes for extensibility as per SOAP 1.1 spec section 4.1
 	Attrs []xml.Attr `xml:",any,attr"`
+
+	// Attachments are MIME parts transmitted alongside the envelope in a
+	// multipart/related message, as per SOAP Messages with Attachments.
+	// They are not part of the XML infoset.
+	Attachments []Attachment `xml:"-"`
+}
+
+// clone returns a copy of the envelope whose header entries and attachments can
+// be modified without affecting the original.
+func (e *Envelope) clone() *Envelope {
+	result := *e
+	if e.Header != nil {
+		header := *e.Header
+		header.Entries = slices.Clone(e.Header.Entries)
+		result.Header = &header
+	}
+	result.Attrs = slices.Clone(e.Attrs)
+	result.Attachments = slices.Clone(e.Attachments)
+	return &result
+}
+
+// elementName returns the name of an element or attribute in the envelope
+// namespace, using the same prefix as the Envelope element.
+func (e *Envelope) elementName(local string) xml.Name {
+	if prefix, _, ok := strings.Cut(e.XMLName.Local, ":"); ok {
+		return xml.Name{Local: prefix + ":" + local}
+	}
+	return xml.Name{Space: e.XMLName.Space, Local: local}
+}
+
+// addHeaderEntry appends an entry to the envelope header, creating the header if needed.
+func (e *Envelope) addHeaderEntry(entry HeaderEntry) {
+	if e.Header == nil {
+		e.Header = &Header{XMLName: e.elementName("Header")}
+	}
+	e.Header.Entries = append(e.Header.Entries, entry)
 }
 
 // Header represents a SOAP header containing header entries.
@@ -74,6 +140,11 @@ type Body struct {
 }
 
 // Fault represents a SOAP fault element as per SOAP 1.1 spec section 4.4.
+//
+// SOAP 1.2 faults (SOAP 1.2 part 1, section 5.4) are decoded into the same
+// type: the 1.2 specific Code, Reason, Node and Role fields are populated, and
+// FaultCode, FaultString, FaultActor and Detail are filled in from them so that
+// callers can handle both versions uniformly.
 type Fault struct {
 	XMLName xml.Name
 
@@ -88,12 +159,258 @@ type Fault struct {
 
 	// Detail is optional and contains application-specific error information
 	Detail *Detail `xml:"detail,omitempty"`
+
+	// Code is the SOAP 1.2 fault code, including any nested subcodes
+	Code *Code `xml:"Code,omitempty"`
+
+	// Reason is the SOAP 1.2 human-readable fault reason
+	Reason *Reason `xml:"Reason,omitempty"`
+
+	// Node is the SOAP 1.2 URI of the node that generated the fault
+	Node string `xml:"Node,omitempty"`
+
+	// Role is the SOAP 1.2 role the node was operating in when the fault occurred
+	Role string `xml:"Role,omitempty"`
+
+	// namespaces are the namespace bindings in scope of the fault element
+	namespaces map[string]string
+}
+
+// Code represents a SOAP 1.2 fault code or subcode.
+type Code struct {
+	// Value is the qualified name of the code, e.g. "env:Sender"
+	Value string `xml:"Value"`
+
+	// Subcode is an optional, more specific application-defined code
+	Subcode *Code `xml:"Subcode,omitempty"`
+}
+
+// Reason represents the SOAP 1.2 fault reason, one text per language.
+type Reason struct {
+	Texts []ReasonText `xml:"Text"`
+}
+
+// ReasonText is a human-readable fault reason in a specific language.
+type ReasonText struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// Text returns the reason text for the given language.
+// Falls back to the first text if no text matches the language.
+func (r *Reason) Text(lang string) string {
+	if r == nil || len(r.Texts) == 0 {
+		return ""
+	}
+	for _, text := range r.Texts {
+		if strings.EqualFold(text.Lang, lang) {
+			return text.Value
+		}
+	}
+	return r.Texts[0].Value
+}
+
+// UnmarshalXML implements [xml.Unmarshaler], decoding both SOAP 1.1 and SOAP 1.2 faults.
+func (f *Fault) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var fault struct {
+		FaultCode   string  `xml:"faultcode"`
+		FaultString string  `xml:"faultstring"`
+		FaultActor  string  `xml:"faultactor"`
+		Detail      *Detail `xml:"detail"`
+		Code        *Code   `xml:"Code"`
+		Reason      *Reason `xml:"Reason"`
+		Node        string  `xml:"Node"`
+		Role        string  `xml:"Role"`
+		Detail12    *Detail `xml:"Detail"`
+	}
+	if err := d.DecodeElement(&fault, &start); err != nil {
+		return err
+	}
+	*f = Fault{
+		XMLName:     start.Name,
+		FaultCode:   fault.FaultCode,
+		FaultString: fault.FaultString,
+		FaultActor:  fault.FaultActor,
+		Detail:      fault.Detail,
+		Code:        fault.Code,
+		Reason:      fault.Reason,
+		Node:        fault.Node,
+		Role:        fault.Role,
+		namespaces:  namespaceScope(nil, start.Attr),
+	}
+	if f.Code != nil && f.FaultCode == "" {
+		f.FaultCode = f.Code.Value
+	}
+	if f.Reason != nil && f.FaultString == "" {
+		f.FaultString = f.Reason.Text("en")
+	}
+	if f.FaultActor == "" {
+		f.FaultActor = f.Node
+	}
+	if f.Detail == nil {
+		f.Detail = fault.Detail12
+	}
+	return nil
+}
+
+// MarshalXML implements [xml.Marshaler]. The fault is encoded in the SOAP 1.2
+// layout when XMLName is in the SOAP 1.2 namespace, or has no namespace and
+// Code is set, and in the SOAP 1.1 layout otherwise.
+//
+// The Fault element declares the envelope namespace itself, and unqualified
+// standard fault codes such as "Client" or "Sender" are qualified with it.
+func (f *Fault) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	namespace := f.XMLName.Space
+	if namespace == "" {
+		namespace = Namespace
+		if f.Code != nil {
+			namespace = Namespace12
+		}
+	}
+	start := xml.StartElement{
+		Name: xml.Name{Local: "soapenv:Fault"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns:soapenv"}, Value: namespace}},
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	var detailName string
+	if namespace == Namespace12 {
+		if f.Code != nil {
+			if err := encodeFaultCode(e, "soapenv:Code", f.Code, true); err != nil {
+				return err
+			}
+		}
+		if f.Reason != nil {
+			reason := xml.StartElement{Name: xml.Name{Local: "soapenv:Reason"}}
+			if err := e.EncodeToken(reason); err != nil {
+				return err
+			}
+			for _, text := range f.Reason.Texts {
+				element := xml.StartElement{Name: xml.Name{Local: "soapenv:Text"}}
+				if text.Lang != "" {
+					element.Attr = []xml.Attr{{Name: xml.Name{Local: "xml:lang"}, Value: text.Lang}}
+				}
+				if err := e.EncodeElement(text.Value, element); err != nil {
+					return err
+				}
+			}
+			if err := e.EncodeToken(reason.End()); err != nil {
+				return err
+			}
+		}
+		if err := encodeOptionalElement(e, "soapenv:Node", f.Node); err != nil {
+			return err
+		}
+		if err := encodeOptionalElement(e, "soapenv:Role", f.Role); err != nil {
+			return err
+		}
+		detailName = "soapenv:Detail"
+	} else {
+		code := f.FaultCode
+		if !strings.Contains(code, ":") && slices.Contains(standardFaultCodes, code) {
+			code = "soapenv:" + code
+		}
+		if err := e.EncodeElement(code, xml.StartElement{Name: xml.Name{Local: "faultcode"}}); err != nil {
+			return err
+		}
+		if err := e.EncodeElement(f.FaultString, xml.StartElement{Name: xml.Name{Local: "faultstring"}}); err != nil {
+			return err
+		}
+		if err := encodeOptionalElement(e, "faultactor", f.FaultActor); err != nil {
+			return err
+		}
+		detailName = "detail"
+	}
+	if f.Detail != nil {
+		detail := xml.StartElement{Name: xml.Name{Local: detailName}}
+		for _, attr := range f.Detail.Attrs {
+			// Declarations of decoded details are written back as literal xmlns attributes
+			if prefix, ok := namespaceDeclaration(attr); ok {
+				attr.Name = xml.Name{Local: "xmlns"}
+				if prefix != "" {
+					attr.Name.Local += ":" + prefix
+				}
+			}
+			detail.Attr = append(detail.Attr, attr)
+		}
+		content := struct {
+			Content []byte `xml:",innerxml"`
+		}{f.Detail.Content}
+		if err := e.EncodeElement(content, detail); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Error implements the error interface, so that handlers served by [Server]
+// can return a fault as an error.
+func (f *Fault) Error() string {
+	return f.String()
+}
+
+// standardFaultCodes are the fault codes defined by SOAP 1.1 and SOAP 1.2.
+var standardFaultCodes = []string{
+	"VersionMismatch", "MustUnderstand", "DataEncodingUnknown", "Client", "Server", "Sender", "Receiver",
+}
+
+// encodeFaultCode encodes a SOAP 1.2 fault code and its subcodes.
+func encodeFaultCode(e *xml.Encoder, name string, code *Code, qualify bool) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	value := code.Value
+	// Only the top-level code is a standard code; subcodes are application defined
+	if qualify && !strings.Contains(value, ":") && slices.Contains(standardFaultCodes, value) {
+		value = "soapenv:" + value
+	}
+	if err := e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: "soapenv:Value"}}); err != nil {
+		return err
+	}
+	if code.Subcode != nil {
+		if err := encodeFaultCode(e, "soapenv:Subcode", code.Subcode, false); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// encodeOptionalElement encodes an element with text content, unless the text is empty.
+func encodeOptionalElement(e *xml.Encoder, name, text string) error {
+	if text == "" {
+		return nil
+	}
+	return e.EncodeElement(text, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// DecodeDetail decodes the first element of the fault detail into v, using
+// [xml.Unmarshal] semantics. If v has an XMLName field with a tag, the detail
+// element must match it, so DecodeDetail can be used to probe for the
+// expected detail type.
+//
+// Namespace prefixes declared on the enclosing elements of a received fault
+// are honored.
+func (f *Fault) DecodeDetail(v any) error {
+	if f.Detail == nil {
+		return fmt.Errorf("failed to decode fault detail: fault has no detail")
+	}
+	if err := decodeScoped(f.Detail.Content, namespaceScope(f.namespaces, f.Detail.Attrs), v); err != nil {
+		return fmt.Errorf("failed to decode fault detail: %w", err)
+	}
+	return nil
 }
 
 // String returns a comprehensive string representation of the SOAP fault for logging.
 func (f *Fault) String() string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "SOAP fault [%s]: %s", f.FaultCode, f.FaultString)
+	if f.Code != nil {
+		for sub := f.Code.Subcode; sub != nil; sub = sub.Subcode {
+			fmt.Fprintf(&b, " (subcode: %s)", sub.Value)
+		}
+	}
 	if f.FaultActor != "" {
 		fmt.Fprintf(&b, " (actor: %s)", f.FaultActor)
 	}
@@ -154,6 +471,14 @@ func WithNamespace(prefix, namespace string) EnvelopeOption {
 	}
 }
 
+// WithVersion sets the SOAP version of the Envelope.
+// This selects the matching envelope namespace, see [Version.Namespace].
+func WithVersion(version Version) EnvelopeOption {
+	return func(cfg *envelopeConfig) {
+		cfg.namespace = version.Namespace()
+	}
+}
+
 // WithBody sets the body for the Envelope.
 func WithBody(body any) EnvelopeOption {
 	return func(cfg *envelopeConfig) {
@@ -50,6 +50,16 @@ func TestNewEnvelope(t *testing.T) {
 				`</soapenv:Envelope>`,
 			}, "\n"),
 		},
+
+		{
+			name: "with SOAP 1.2 version",
+			opts: []EnvelopeOption{WithVersion(Version12)},
+			want: strings.Join([]string{
+				`<soapenv:Envelope xmlns:soapenv="http://www.w3.org/2003/05/soap-envelope">`,
+				`  <soapenv:Body></soapenv:Body>`,
+				`</soapenv:Envelope>`,
+			}, "\n"),
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -192,6 +202,66 @@ func TestFaultHandling(t *testing.T) {
 	}
 }
 
+func TestFaultUnmarshalSOAP12(t *testing.T) {
+	t.Parallel()
+	data := []byte(`<env:Fault xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:m="http://example.com/errors">
+		<env:Code>
+			<env:Value>env:Sender</env:Value>
+			<env:Subcode>
+				<env:Value>m:InvalidAccount</env:Value>
+				<env:Subcode><env:Value>m:Closed</env:Value></env:Subcode>
+			</env:Subcode>
+		</env:Code>
+		<env:Reason>
+			<env:Text xml:lang="de">Ungültiges Konto</env:Text>
+			<env:Text xml:lang="en">Invalid account</env:Text>
+		</env:Reason>
+		<env:Node>http://example.com/node</env:Node>
+		<env:Role>http://www.w3.org/2003/05/soap-envelope/role/ultimateReceiver</env:Role>
+		<env:Detail><m:account>42</m:account></env:Detail>
+	</env:Fault>`)
+	var fault Fault
+	if err := xml.Unmarshal(data, &fault); err != nil {
+		t.Fatalf("Failed to unmarshal SOAP 1.2 fault: %v", err)
+	}
+	if fault.XMLName.Space != Namespace12 || fault.XMLName.Local != "Fault" {
+		t.Errorf("Unexpected fault name: %v", fault.XMLName)
+	}
+	if fault.Code == nil || fault.Code.Value != "env:Sender" {
+		t.Fatalf("Expected code 'env:Sender', got: %+v", fault.Code)
+	}
+	if fault.Code.Subcode == nil || fault.Code.Subcode.Value != "m:InvalidAccount" {
+		t.Fatalf("Expected subcode 'm:InvalidAccount', got: %+v", fault.Code.Subcode)
+	}
+	if fault.Code.Subcode.Subcode == nil || fault.Code.Subcode.Subcode.Value != "m:Closed" {
+		t.Fatalf("Expected nested subcode 'm:Closed', got: %+v", fault.Code.Subcode.Subcode)
+	}
+	if got := fault.Reason.Text("de"); got != "Ungültiges Konto" {
+		t.Errorf("Expected German reason, got: %s", got)
+	}
+	if fault.Role != "http://www.w3.org/2003/05/soap-envelope/role/ultimateReceiver" {
+		t.Errorf("Unexpected role: %s", fault.Role)
+	}
+	// SOAP 1.1 fields are populated from their SOAP 1.2 counterparts.
+	if fault.FaultCode != "env:Sender" {
+		t.Errorf("Expected fault code 'env:Sender', got: %s", fault.FaultCode)
+	}
+	if fault.FaultString != "Invalid account" {
+		t.Errorf("Expected fault string 'Invalid account', got: %s", fault.FaultString)
+	}
+	if fault.FaultActor != "http://example.com/node" {
+		t.Errorf("Expected fault actor from node, got: %s", fault.FaultActor)
+	}
+	if fault.Detail == nil || string(fault.Detail.Content) != "<m:account>42</m:account>" {
+		t.Errorf("Unexpected detail: %+v", fault.Detail)
+	}
+	expected := "SOAP fault [env:Sender]: Invalid account (subcode: m:InvalidAccount) (subcode: m:Closed)" +
+		" (actor: http://example.com/node) - detail: <m:account>42</m:account>"
+	if fault.String() != expected {
+		t.Errorf("Expected string %q, got: %s", expected, fault.String())
+	}
+}
+
 func TestFaultStringInterface(t *testing.T) {
 	t.Parallel()
 	// Test fault with all fields
@@ -238,6 +308,47 @@ func TestFaultStringInterface(t *testing.T) {
 	}
 }
 
+func TestFault_DecodeDetail(t *testing.T) {
+	t.Parallel()
+	// The detail uses a prefix declared on the Envelope
+	data := `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="http://example.com/errors">
+		<s:Body>
+			<s:Fault>
+				<faultcode>s:Client</faultcode>
+				<faultstring>Invalid account</faultstring>
+				<detail><m:InvalidAccount><m:AccountId>42</m:AccountId></m:InvalidAccount></detail>
+			</s:Fault>
+		</s:Body>
+	</s:Envelope>`
+	var env Envelope
+	if err := xml.Unmarshal([]byte(data), &env); err != nil {
+		t.Fatalf("Failed to unmarshal envelope: %v", err)
+	}
+	fault := checkForSOAPFault(&env)
+	if fault == nil {
+		t.Fatal("Expected a SOAP fault")
+	}
+	var invalidAccount struct {
+		XMLName   xml.Name `xml:"http://example.com/errors InvalidAccount"`
+		AccountID string   `xml:"http://example.com/errors AccountId"`
+	}
+	if err := fault.DecodeDetail(&invalidAccount); err != nil {
+		t.Fatalf("DecodeDetail() error = %v", err)
+	}
+	if invalidAccount.AccountID != "42" {
+		t.Errorf("Expected account ID '42', got: %q", invalidAccount.AccountID)
+	}
+	var otherFault struct {
+		XMLName xml.Name `xml:"http://example.com/errors InsufficientFunds"`
+	}
+	if err := fault.DecodeDetail(&otherFault); err == nil {
+		t.Error("Expected an error decoding a detail of another type")
+	}
+	if err := (&Fault{FaultCode: "Server"}).DecodeDetail(&otherFault); err == nil {
+		t.Error("Expected an error decoding a fault without detail")
+	}
+}
+
 func TestHeaderEntryMustUnderstand(t *testing.T) {
 	t.Parallel()
 	// Test mustUnderstand true
@@ -7,7 +7,8 @@ type Error struct {
 	// StatusCode is the HTTP status code of the response.
 	StatusCode int
 
-	// ResponseBody is the raw HTTP response body.
+	// ResponseBody is the raw HTTP response body. It is nil for faults decoded
+	// from a streamed response by [Client.CallDecode].
 	ResponseBody []byte
 
 	// Envelope is the SOAP envelope that was received, nil if parsing failed.
@@ -24,3 +25,15 @@ func (e *Error) Error() string {
 	}
 	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, string(e.ResponseBody))
 }
+
+// ResponseTooLargeError is returned when a response body exceeds the limit set
+// with [WithMaxResponseBytes].
+type ResponseTooLargeError struct {
+	// Limit is the maximum response size in bytes.
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the limit of %d bytes", e.Limit)
+}
@@ -2,7 +2,6 @@ package globalweather
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 
 	soap "github.com/way-platform/soap-go"
@@ -40,17 +39,28 @@ func (c *Client) GetWeather(
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://www.webserviceX.NET/GetWeather", reqEnvelope, opts...)
+	var result GetWeatherResponseWrapper
+	_, err = c.CallDecode(ctx, "http://www.webserviceX.NET/GetWeather", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result GetWeatherResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// GetWeatherBatch executes GetWeather for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetWeatherBatch(
+	ctx context.Context,
+	reqs []*GetWeatherWrapper,
+	opts ...soap.BatchOption,
+) []soap.BatchResult[*GetWeatherResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetWeatherWrapper) (*GetWeatherResponseWrapper, error) {
+		return c.GetWeather(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // GetCitiesByCountry Get all major                 cities by country name(full / part).
 func (c *Client) GetCitiesByCountry(
 	ctx context.Context,
@@ -61,13 +71,24 @@ func (c *Client) GetCitiesByCountry(
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://www.webserviceX.NET/GetCitiesByCountry", reqEnvelope, opts...)
+	var result GetCitiesByCountryResponseWrapper
+	_, err = c.CallDecode(ctx, "http://www.webserviceX.NET/GetCitiesByCountry", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result GetCitiesByCountryResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
+
+// GetCitiesByCountryBatch executes GetCitiesByCountry for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetCitiesByCountryBatch(
+	ctx context.Context,
+	reqs []*GetCitiesByCountryWrapper,
+	opts ...soap.BatchOption,
+) []soap.BatchResult[*GetCitiesByCountryResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetCitiesByCountryWrapper) (*GetCitiesByCountryResponseWrapper, error) {
+		return c.GetCitiesByCountry(ctx, req, callOpts...)
+	}, opts...)
+}
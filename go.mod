@@ -7,4 +7,7 @@ toolchain go1.25.1
 require (
 	github.com/google/go-cmp v0.7.0
 	golang.org/x/text v0.28.0
+	software.sslmate.com/src/go-pkcs12 v0.7.3
 )
+
+require golang.org/x/crypto v0.11.0 // indirect
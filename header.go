@@ -0,0 +1,342 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrHeaderNotFound is returned by [Envelope.DecodeHeader] when the envelope
+// has no header entry with the requested name.
+var ErrHeaderNotFound = errors.New("header not found")
+
+// AddHeader marshals v and appends it to the envelope header as a header entry.
+//
+// v is marshalled with [xml.Marshal], so its XMLName determines the element
+// name of the entry; a []byte value is used as raw XML. When mustUnderstand
+// is set, the entry is marked as mandatory for the recipient. A non-empty
+// actor targets the entry at a specific recipient, using the SOAP 1.1 actor
+// or SOAP 1.2 role attribute depending on the envelope namespace.
+func (e *Envelope) AddHeader(v any, mustUnderstand bool, actor string) error {
+	data, ok := v.([]byte)
+	if !ok {
+		var err error
+		if data, err = xml.Marshal(v); err != nil {
+			return fmt.Errorf("failed to marshal header: %w", err)
+		}
+	}
+	entry, err := parseHeaderEntry(data)
+	if err != nil {
+		return err
+	}
+	if mustUnderstand {
+		entry.Attrs = append(entry.Attrs, xml.Attr{Name: e.elementName("mustUnderstand"), Value: "1"})
+	}
+	if actor != "" {
+		actorAttr := "actor"
+		if e.namespace() == Namespace12 {
+			actorAttr = "role"
+		}
+		entry.Attrs = append(entry.Attrs, xml.Attr{Name: e.elementName(actorAttr), Value: actor})
+	}
+	e.addHeaderEntry(entry)
+	return nil
+}
+
+// DecodeHeader decodes the first header entry with the given name into v,
+// using [xml.Unmarshal] semantics. It returns [ErrHeaderNotFound] if the
+// envelope has no such entry.
+//
+// Namespace prefixes declared on the Envelope and Header elements are
+// honored, so entries of received envelopes decode the same way as entries
+// added with [Envelope.AddHeader].
+func (e *Envelope) DecodeHeader(name xml.Name, v any) error {
+	if e.Header == nil {
+		return fmt.Errorf("%w: {%s}%s", ErrHeaderNotFound, name.Space, name.Local)
+	}
+	scope := namespaceScope(nil, e.Attrs)
+	scope = namespaceScope(scope, e.Header.Attrs)
+	for _, entry := range e.Header.Entries {
+		entryScope := namespaceScope(scope, entry.Attrs)
+		if resolveName(entry.XMLName, entryScope) != name {
+			continue
+		}
+		if err := decodeScoped(headerEntryXML(entry), scope, v); err != nil {
+			return fmt.Errorf("failed to decode header: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: {%s}%s", ErrHeaderNotFound, name.Space, name.Local)
+}
+
+// DecodeBody decodes the first element of the body into v, using
+// [xml.Unmarshal] semantics. Namespace prefixes declared on the Envelope and
+// Body elements are honored.
+func (e *Envelope) DecodeBody(v any) error {
+	scope := namespaceScope(namespaceScope(nil, e.Attrs), e.Body.Attrs)
+	if err := decodeScoped(e.Body.Content, scope, v); err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+	return nil
+}
+
+// BodyElementName returns the qualified name of the first element of the body.
+// It reports false if the body has no element.
+func (e *Envelope) BodyElementName() (xml.Name, bool) {
+	var start xml.StartElement
+	if err := e.DecodeBody(&startElementRecorder{start: &start}); err != nil {
+		return xml.Name{}, false
+	}
+	return start.Name, true
+}
+
+// startElementRecorder records the start element it is decoded from.
+type startElementRecorder struct {
+	start *xml.StartElement
+}
+
+// UnmarshalXML implements [xml.Unmarshaler].
+func (r *startElementRecorder) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*r.start = start
+	return d.Skip()
+}
+
+// WithHeader adds a header entry to every request, as by [Envelope.AddHeader].
+// It can be passed per call to send a header with a single request.
+func WithHeader(v any, mustUnderstand bool, actor string) ClientOption {
+	return func(c *clientConfig) {
+		// Clip so per-call options never write into the client's backing array
+		c.headers = append(slices.Clip(c.headers), headerValue{value: v, mustUnderstand: mustUnderstand, actor: actor})
+	}
+}
+
+// headerValue is a header entry configured with [WithHeader].
+type headerValue struct {
+	value          any
+	mustUnderstand bool
+	actor          string
+}
+
+// WithResponseHeader decodes the response header entry with the element name
+// of v, such as a generated header type, into v, as by [Envelope.DecodeHeader].
+// v must be a pointer, and is left unchanged if the response has no such
+// entry. The headers of SOAP fault responses are decoded too.
+//
+// It is meant to be passed per call, to read the headers of operations of
+// generated clients, which return only the body.
+func WithResponseHeader(v any) ClientOption {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return withError(fmt.Errorf("failed to marshal response header: %w", err))
+	}
+	var start xml.StartElement
+	if err := decodeScoped(data, nil, &startElementRecorder{start: &start}); err != nil {
+		return withError(fmt.Errorf("failed to marshal response header: %w", err))
+	}
+	return func(c *clientConfig) {
+		c.responseHeaders = append(slices.Clip(c.responseHeaders), responseHeader{name: start.Name, value: v})
+	}
+}
+
+// responseHeader is a header entry to decode configured with
+// [WithResponseHeader].
+type responseHeader struct {
+	name  xml.Name
+	value any
+}
+
+// decodeResponseHeaders decodes the response headers of the configuration
+// from the response envelope, or the envelope of a SOAP fault, and returns
+// the error of the call or of decoding.
+func (c clientConfig) decodeResponseHeaders(env *Envelope, err error) error {
+	if len(c.responseHeaders) == 0 {
+		return err
+	}
+	if soapErr := (*Error)(nil); env == nil && errors.As(err, &soapErr) {
+		env = soapErr.Envelope
+	}
+	if env == nil {
+		return err
+	}
+	for _, header := range c.responseHeaders {
+		if decodeErr := env.DecodeHeader(header.name, header.value); decodeErr != nil &&
+			!errors.Is(decodeErr, ErrHeaderNotFound) && err == nil {
+			err = decodeErr
+		}
+	}
+	return err
+}
+
+// namespace returns the namespace of the envelope element.
+func (e *Envelope) namespace() string {
+	if e.XMLName.Space != "" {
+		return e.XMLName.Space
+	}
+	prefix, _, _ := strings.Cut(e.XMLName.Local, ":")
+	return namespaceScope(nil, e.Attrs)[prefix]
+}
+
+// parseHeaderEntry converts a serialized element into a header entry that
+// marshals back to the same element.
+func parseHeaderEntry(data []byte) (HeaderEntry, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var entry HeaderEntry
+	var contentStart int64
+	depth := 0
+	for {
+		offset := d.InputOffset()
+		token, err := d.RawToken()
+		if err == io.EOF {
+			return HeaderEntry{}, fmt.Errorf("failed to parse header: unexpected end of element")
+		}
+		if err != nil {
+			return HeaderEntry{}, fmt.Errorf("failed to parse header: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				// Prefixes are kept as written, so the element marshals unchanged
+				entry.XMLName = xml.Name{Local: qualifiedName(t.Name.Space, t.Name.Local)}
+				for _, attr := range t.Attr {
+					entry.Attrs = append(entry.Attrs, xml.Attr{
+						Name:  xml.Name{Local: qualifiedName(attr.Name.Space, attr.Name.Local)},
+						Value: attr.Value,
+					})
+				}
+				contentStart = d.InputOffset()
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				entry.Content = data[contentStart:offset]
+				return entry, nil
+			}
+		}
+	}
+}
+
+// namespaceScope returns the namespace bindings of scope extended with the
+// declarations among attrs. The default namespace is keyed by the empty prefix.
+func namespaceScope(scope map[string]string, attrs []xml.Attr) map[string]string {
+	result := make(map[string]string, len(scope)+len(attrs))
+	for prefix, namespace := range scope {
+		result[prefix] = namespace
+	}
+	for _, attr := range attrs {
+		if prefix, ok := namespaceDeclaration(attr); ok {
+			result[prefix] = attr.Value
+		}
+	}
+	return result
+}
+
+// namespaceDeclaration reports whether the attribute declares a namespace
+// prefix, as decoded (xmlns:p, parsed into Space and Local) or as written by
+// this package (a literal "xmlns:p" local name).
+func namespaceDeclaration(attr xml.Attr) (string, bool) {
+	switch {
+	case attr.Name.Space == "xmlns":
+		return attr.Name.Local, true
+	case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+		return "", true
+	case attr.Name.Space == "" && strings.HasPrefix(attr.Name.Local, "xmlns:"):
+		return strings.TrimPrefix(attr.Name.Local, "xmlns:"), true
+	}
+	return "", false
+}
+
+// resolveName resolves a name written with a literal prefix against the scope.
+// Names that already carry a namespace are returned unchanged.
+func resolveName(name xml.Name, scope map[string]string) xml.Name {
+	if name.Space != "" {
+		return name
+	}
+	prefix, local, ok := strings.Cut(name.Local, ":")
+	if !ok {
+		return xml.Name{Space: scope[""], Local: name.Local}
+	}
+	return xml.Name{Space: scope[prefix], Local: local}
+}
+
+// decodeScoped decodes the first element of data into v, resolving namespace
+// prefixes that data uses but does not declare against scope.
+func decodeScoped(data []byte, scope map[string]string, v any) error {
+	var buf bytes.Buffer
+	buf.WriteString("<soapgo-scope")
+	for prefix, namespace := range scope {
+		writeNamespaceDeclaration(&buf, prefix, namespace)
+	}
+	buf.WriteString(">")
+	buf.Write(data)
+	buf.WriteString("</soapgo-scope>")
+	d := xml.NewDecoder(&buf)
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("no element found")
+			}
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return d.DecodeElement(v, &t)
+		case xml.EndElement:
+			return fmt.Errorf("no element found")
+		}
+	}
+}
+
+// headerEntryXML serializes a header entry as a standalone element.
+func headerEntryXML(entry HeaderEntry) []byte {
+	var buf bytes.Buffer
+	var declarations bytes.Buffer
+	var attrs bytes.Buffer
+	elementName := entry.XMLName.Local
+	if entry.XMLName.Space != "" {
+		elementName = "soapgo-header:" + entry.XMLName.Local
+		writeNamespaceDeclaration(&declarations, "soapgo-header", entry.XMLName.Space)
+	}
+	for i, attr := range entry.Attrs {
+		if prefix, ok := namespaceDeclaration(attr); ok {
+			writeNamespaceDeclaration(&declarations, prefix, attr.Value)
+			continue
+		}
+		attrName := attr.Name.Local
+		if attr.Name.Space != "" {
+			prefix := "soapgo-attr" + strconv.Itoa(i)
+			attrName = prefix + ":" + attr.Name.Local
+			writeNamespaceDeclaration(&declarations, prefix, attr.Name.Space)
+		}
+		attrs.WriteString(" " + attrName + `="`)
+		_ = xml.EscapeText(&attrs, []byte(attr.Value))
+		attrs.WriteString(`"`)
+	}
+	buf.WriteString("<" + elementName)
+	buf.Write(declarations.Bytes())
+	buf.Write(attrs.Bytes())
+	buf.WriteString(">")
+	buf.Write(entry.Content)
+	buf.WriteString("</" + elementName + ">")
+	return buf.Bytes()
+}
+
+// writeNamespaceDeclaration writes a namespace declaration attribute.
+func writeNamespaceDeclaration(buf *bytes.Buffer, prefix, namespace string) {
+	if prefix == "" {
+		buf.WriteString(` xmlns="`)
+	} else {
+		buf.WriteString(` xmlns:` + prefix + `="`)
+	}
+	_ = xml.EscapeText(buf, []byte(namespace))
+	buf.WriteString(`"`)
+}
@@ -0,0 +1,224 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type credentialsHeader struct {
+	XMLName xml.Name `xml:"http://example.com/auth Credentials"`
+	APIKey  string   `xml:"ApiKey"`
+	Tenant  string   `xml:"tenant,attr,omitempty"`
+}
+
+func TestEnvelope_AddHeader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		version        Version
+		mustUnderstand bool
+		actor          string
+		expected       []string
+	}{
+		{
+			name:     "plain header",
+			version:  Version11,
+			expected: []string{`<Credentials xmlns="http://example.com/auth" tenant="acme"><ApiKey>secret</ApiKey></Credentials>`},
+		},
+		{
+			name:           "SOAP 1.1 mustUnderstand and actor",
+			version:        Version11,
+			mustUnderstand: true,
+			actor:          "http://example.com/gateway",
+			expected:       []string{`soapenv:mustUnderstand="1"`, `soapenv:actor="http://example.com/gateway"`},
+		},
+		{
+			name:     "SOAP 1.2 role",
+			version:  Version12,
+			actor:    "http://www.w3.org/2003/05/soap-envelope/role/next",
+			expected: []string{`soapenv:role="http://www.w3.org/2003/05/soap-envelope/role/next"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env, err := NewEnvelope(WithVersion(tt.version), WithBody([]byte(`<request/>`)))
+			if err != nil {
+				t.Fatalf("Failed to create envelope: %v", err)
+			}
+			header := credentialsHeader{APIKey: "secret", Tenant: "acme"}
+			if err := env.AddHeader(header, tt.mustUnderstand, tt.actor); err != nil {
+				t.Fatalf("AddHeader() error = %v", err)
+			}
+			data, err := xml.Marshal(env)
+			if err != nil {
+				t.Fatalf("Failed to marshal envelope: %v", err)
+			}
+			if !strings.Contains(string(data), "<soapenv:Header>") {
+				t.Errorf("Expected a soapenv:Header element, got: %s", data)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(data), expected) {
+					t.Errorf("Expected %s in: %s", expected, data)
+				}
+			}
+			var decoded credentialsHeader
+			if err := env.DecodeHeader(xml.Name{Space: "http://example.com/auth", Local: "Credentials"}, &decoded); err != nil {
+				t.Fatalf("DecodeHeader() error = %v", err)
+			}
+			if decoded.APIKey != "secret" || decoded.Tenant != "acme" {
+				t.Errorf("Unexpected decoded header: %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestEnvelope_DecodeHeader(t *testing.T) {
+	t.Parallel()
+	// Prefixes declared on the Envelope are used inside the header entries
+	data := `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" xmlns:a="http://example.com/auth">
+		<s:Header>
+			<a:Session s:mustUnderstand="1"><a:Token>abc</a:Token></a:Session>
+			<a:Credentials tenant="acme"><a:ApiKey>secret</a:ApiKey></a:Credentials>
+		</s:Header>
+		<s:Body><response/></s:Body>
+	</s:Envelope>`
+	var env Envelope
+	if err := xml.Unmarshal([]byte(data), &env); err != nil {
+		t.Fatalf("Failed to unmarshal envelope: %v", err)
+	}
+	var credentials struct {
+		XMLName xml.Name `xml:"http://example.com/auth Credentials"`
+		APIKey  string   `xml:"http://example.com/auth ApiKey"`
+		Tenant  string   `xml:"tenant,attr"`
+	}
+	if err := env.DecodeHeader(xml.Name{Space: "http://example.com/auth", Local: "Credentials"}, &credentials); err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+	if credentials.APIKey != "secret" || credentials.Tenant != "acme" {
+		t.Errorf("Unexpected decoded header: %+v", credentials)
+	}
+	var session struct {
+		Token string `xml:"http://example.com/auth Token"`
+	}
+	if err := env.DecodeHeader(xml.Name{Space: "http://example.com/auth", Local: "Session"}, &session); err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+	if session.Token != "abc" {
+		t.Errorf("Expected token 'abc', got: %q", session.Token)
+	}
+	err := env.DecodeHeader(xml.Name{Space: "http://example.com/auth", Local: "Missing"}, &session)
+	if !errors.Is(err, ErrHeaderNotFound) {
+		t.Errorf("Expected ErrHeaderNotFound, got: %v", err)
+	}
+}
+
+func TestClient_WithHeader(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		respEnv, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithMaxRetries(0),
+		WithHeader(credentialsHeader{APIKey: "default"}, true, ""),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+	trace := []byte(`<Trace xmlns="http://example.com/trace">42</Trace>`)
+	if _, err := client.Call(context.Background(), "", reqEnv, WithHeader(trace, false, "")); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if _, err := client.Call(context.Background(), "", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	for _, request := range requests {
+		if !strings.Contains(request, `<ApiKey>default</ApiKey>`) || !strings.Contains(request, `soapenv:mustUnderstand="1"`) {
+			t.Errorf("Expected the default header in every request: %s", request)
+		}
+	}
+	if !strings.Contains(requests[0], string(trace)) {
+		t.Errorf("Expected the per-call header in the first request: %s", requests[0])
+	}
+	if strings.Contains(requests[1], "Trace") {
+		t.Errorf("Expected no per-call header in the second request: %s", requests[1])
+	}
+	if reqEnv.Header != nil {
+		t.Error("Expected the caller's envelope to be left unmodified")
+	}
+}
+
+func TestClient_WithResponseHeader(t *testing.T) {
+	t.Parallel()
+	type quotaHeader struct {
+		XMLName   xml.Name `xml:"http://example.com/quota Quota"`
+		Remaining int      `xml:"Remaining"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `<response>OK</response>`
+		if r.URL.Path == "/fault" {
+			w.WriteHeader(http.StatusInternalServerError)
+			body = `<soapenv:Fault><faultcode>soapenv:Client</faultcode><faultstring>Quota exceeded</faultstring></soapenv:Fault>`
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = io.WriteString(w, `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:q="http://example.com/quota">`+
+			`<soapenv:Header><q:Quota><q:Remaining>5</q:Remaining></q:Quota></soapenv:Header>`+
+			`<soapenv:Body>`+body+`</soapenv:Body></soapenv:Envelope>`)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+
+	var quota quotaHeader
+	var resp struct {
+		Value string `xml:",chardata"`
+	}
+	if _, err := client.CallDecode(context.Background(), "", reqEnv, &resp, WithResponseHeader(&quota)); err != nil {
+		t.Fatalf("Client.CallDecode() error = %v", err)
+	}
+	if quota.Remaining != 5 || resp.Value != "OK" {
+		t.Errorf("Expected the response header and body, got: %+v, %+v", quota, resp)
+	}
+
+	var faultQuota quotaHeader
+	_, err = client.Call(context.Background(), "", reqEnv, WithEndpoint(server.URL+"/fault"), WithResponseHeader(&faultQuota))
+	var soapErr *Error
+	if !errors.As(err, &soapErr) || soapErr.Fault == nil {
+		t.Fatalf("Expected a SOAP fault, got: %v", err)
+	}
+	if faultQuota.Remaining != 5 {
+		t.Errorf("Expected the response header of the fault, got: %+v", faultQuota)
+	}
+
+	var missing struct {
+		XMLName xml.Name `xml:"http://example.com/quota Missing"`
+		Value   string   `xml:",chardata"`
+	}
+	if _, err := client.Call(context.Background(), "", reqEnv, WithResponseHeader(&missing)); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if missing.Value != "" {
+		t.Errorf("Expected a missing header to be left unchanged, got: %+v", missing)
+	}
+}
@@ -14,6 +14,7 @@ var (
 	HTTPResponseIdent = GoIdent{GoImportPath: "net/http", GoName: "Response"}
 	IOReaderIdent     = GoIdent{GoImportPath: "io", GoName: "Reader"}
 	IOReadCloserIdent = GoIdent{GoImportPath: "io", GoName: "ReadCloser"}
+	HTTPHandlerIdent  = GoIdent{GoImportPath: "net/http", GoName: "Handler"}
 
 	// Standard library functions
 	FmtSprintfIdent                = GoIdent{GoImportPath: "fmt", GoName: "Sprintf"}
@@ -24,17 +25,39 @@ var (
 	HTTPStatusOKIdent              = GoIdent{GoImportPath: "net/http", GoName: "StatusOK"}
 	BytesNewReaderIdent            = GoIdent{GoImportPath: "bytes", GoName: "NewReader"}
 	IOReadAllIdent                 = GoIdent{GoImportPath: "io", GoName: "ReadAll"}
+	ErrorsAsIdent                  = GoIdent{GoImportPath: "errors", GoName: "As"}
 
 	// SOAP library types
-	SOAPClientIdent       = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Client"}
-	SOAPClientOptionIdent = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "ClientOption"}
-	SOAPNewClientIdent    = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "NewClient"}
-	SOAPWithEndpointIdent = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithEndpoint"}
-	SOAPEnvelopeIdent     = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Envelope"}
-	SOAPBodyIdent         = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Body"}
-	SOAPNamespaceIdent    = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Namespace"}
-	SOAPNewEnvelopeIdent  = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "NewEnvelope"}
-	SOAPWithBodyIdent     = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithBody"}
+	SOAPClientIdent              = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Client"}
+	SOAPClientOptionIdent        = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "ClientOption"}
+	SOAPNewClientIdent           = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "NewClient"}
+	SOAPWithEndpointIdent        = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithEndpoint"}
+	SOAPWithEndpointsIdent       = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithEndpoints"}
+	SOAPEnvelopeIdent            = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Envelope"}
+	SOAPBodyIdent                = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Body"}
+	SOAPNamespaceIdent           = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Namespace"}
+	SOAPNewEnvelopeIdent         = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "NewEnvelope"}
+	SOAPWithBodyIdent            = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithBody"}
+	SOAPWithVersionIdent         = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithVersion"}
+	SOAPVersion12Ident           = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Version12"}
+	SOAPWithSOAPVersionIdent     = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithSOAPVersion"}
+	SOAPWithMTOMIdent            = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithMTOM"}
+	SOAPWithXOPElementsIdent     = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithXOPElements"}
+	SOAPWithIdempotentIdent      = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithIdempotent"}
+	SOAPWithActionRateLimitIdent = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "WithActionRateLimit"}
+	SOAPBase64BinaryIdent        = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Base64Binary"}
+	SOAPAttachmentIdent          = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Attachment"}
+	SOAPBatchIdent               = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Batch"}
+	SOAPBatchCallOptionsIdent    = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "BatchCallOptions"}
+	SOAPBatchOptionIdent         = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "BatchOption"}
+	SOAPBatchResultIdent         = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "BatchResult"}
+	SOAPErrorIdent               = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Error"}
+	SOAPServerIdent              = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Server"}
+	SOAPNewServerIdent           = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "NewServer"}
+	SOAPServerOptionIdent        = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "ServerOption"}
+	SOAPHandleIdent              = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Handle"}
+	SOAPFaultIdent               = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Fault"}
+	SOAPDetailIdent              = GoIdent{GoImportPath: "github.com/way-platform/soap-go", GoName: "Detail"}
 
 	// Built-in types (no import path needed)
 	StringIdent = GoIdent{GoImportPath: "", GoName: "string"}
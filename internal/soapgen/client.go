@@ -2,6 +2,9 @@ package soapgen
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/way-platform/soap-go/internal/codegen"
@@ -50,7 +53,9 @@ func (g *Generator) generateClientFile(packageName, filename string) (*codegen.F
 	g.generateClientStruct(file)
 
 	// Generate NewClient function
-	g.generateNewClientFunction(file)
+	if err := g.generateNewClientFunction(file); err != nil {
+		return nil, err
+	}
 
 	// Generate operation methods
 	err := g.generateOperationMethods(file)
@@ -81,15 +86,46 @@ func (g *Generator) generateClientStruct(file *codegen.File) {
 }
 
 // generateNewClientFunction generates the NewClient constructor
-func (g *Generator) generateNewClientFunction(file *codegen.File) {
+func (g *Generator) generateNewClientFunction(file *codegen.File) error {
 	// Extract default endpoint from service definitions
-	endpoint := g.getDefaultEndpoint()
+	endpoints := g.getEndpoints()
+	rateLimits, err := g.getActionRateLimits()
+	if err != nil {
+		return err
+	}
 
 	file.P("// NewClient creates a new SOAP client.")
 	file.P("func NewClient(opts ...ClientOption) (*Client, error) {")
-	if endpoint != "" {
+	isSOAP12 := g.getSOAPVersion() == soap12
+	if len(endpoints) > 0 || isSOAP12 || len(rateLimits) > 0 {
 		file.P("\tsoapOpts := append([]", file.QualifiedGoIdent(codegen.SOAPClientOptionIdent), "{")
-		file.P("\t\t", file.QualifiedGoIdent(codegen.SOAPWithEndpointIdent), "(\"", endpoint, "\"),")
+		switch len(endpoints) {
+		case 0:
+		case 1:
+			file.P("\t\t", file.QualifiedGoIdent(codegen.SOAPWithEndpointIdent), "(\"", endpoints[0], "\"),")
+		default:
+			// Services with several ports fail over between their addresses
+			file.P("\t\t", file.QualifiedGoIdent(codegen.SOAPWithEndpointsIdent), "(")
+			for _, endpoint := range endpoints {
+				file.P("\t\t\t\"", endpoint, "\",")
+			}
+			file.P("\t\t),")
+		}
+		if isSOAP12 {
+			file.P(
+				"\t\t",
+				file.QualifiedGoIdent(codegen.SOAPWithSOAPVersionIdent),
+				"(",
+				file.QualifiedGoIdent(codegen.SOAPVersion12Ident),
+				"),",
+			)
+		}
+		for _, limit := range rateLimits {
+			file.P(
+				"\t\t", file.QualifiedGoIdent(codegen.SOAPWithActionRateLimitIdent), "(\"", limit.action, "\", ",
+				strconv.FormatFloat(limit.PerSecond, 'g', -1, 64), ", ", max(limit.Burst, 1), "),",
+			)
+		}
 		file.P("\t}, opts...)")
 		file.P("\tsoapClient, err := ", file.QualifiedGoIdent(codegen.SOAPNewClientIdent), "(soapOpts...)")
 	} else {
@@ -107,21 +143,76 @@ func (g *Generator) generateNewClientFunction(file *codegen.File) {
 	file.P("\t}, nil")
 	file.P("}")
 	file.P()
+	return nil
+}
+
+// actionRateLimit is a configured rate limit of an operation, by SOAP action
+type actionRateLimit struct {
+	RateLimit
+	action string
+}
+
+// getActionRateLimits returns the configured operation rate limits, in WSDL order
+func (g *Generator) getActionRateLimits() ([]actionRateLimit, error) {
+	var limits []actionRateLimit
+	found := make(map[string]bool)
+	for _, binding := range g.getSOAPBindings() {
+		portType := g.getPortTypeForBinding(binding)
+		if portType == nil {
+			continue
+		}
+		for _, operation := range portType.Operations {
+			limit, ok := g.config.RateLimits[operation.Name]
+			if !ok || found[operation.Name] {
+				continue
+			}
+			if !(limit.PerSecond > 0) || limit.Burst < 0 {
+				return nil, fmt.Errorf("invalid rate limit %v/s, burst %d for operation %s", limit.PerSecond, limit.Burst, operation.Name)
+			}
+			action := g.getSOAPActionForOperation(operation.Name, binding)
+			if action == "" {
+				return nil, fmt.Errorf("rate limited operation %s has no SOAP action", operation.Name)
+			}
+			found[operation.Name] = true
+			limits = append(limits, actionRateLimit{RateLimit: limit, action: action})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(g.config.RateLimits)) {
+		if !found[name] {
+			return nil, fmt.Errorf("rate limited operation %s not found", name)
+		}
+	}
+	return limits, nil
 }
 
-// getDefaultEndpoint extracts the default endpoint from service definitions
-func (g *Generator) getDefaultEndpoint() string {
+// getEndpoints extracts the addresses of the service ports, in WSDL order.
+// Only ports of the client's bindings for the port type of its first binding
+// are equivalent endpoints; ports of other bindings are skipped.
+func (g *Generator) getEndpoints() []string {
+	bindings := g.getSOAPBindings()
+	if len(bindings) == 0 {
+		return nil
+	}
+	portType := g.getPortTypeForBinding(bindings[0])
+	var endpoints []string
 	for _, service := range g.definitions.Service {
 		for _, port := range service.Ports {
-			if port.SOAP11Address != nil {
-				return port.SOAP11Address.Location
+			i := slices.IndexFunc(bindings, func(binding *wsdl.Binding) bool {
+				return binding.Name == extractLocalName(port.Binding)
+			})
+			if i < 0 || g.getPortTypeForBinding(bindings[i]) != portType {
+				continue
 			}
-			if port.SOAP12Address != nil {
-				return port.SOAP12Address.Location
+			address := port.SOAP11Address
+			if bindings[i].SOAP11Binding == nil {
+				address = port.SOAP12Address
+			}
+			if address != nil && address.Location != "" && !slices.Contains(endpoints, address.Location) {
+				endpoints = append(endpoints, address.Location)
 			}
 		}
 	}
-	return ""
+	return endpoints
 }
 
 // generateOperationMethods generates methods for each SOAP operation
@@ -220,98 +311,242 @@ func (g *Generator) generateOperationMethod(
 		}
 	}
 
-	// Generate different method signatures for one-way vs request-response operations
-	if isOneWay {
-		// One-way operation: return only error
-		file.P(
-			"func (c *Client) ",
-			methodName,
-			"(ctx ",
-			file.QualifiedGoIdent(codegen.ContextIdent),
-			", req *",
-			inputType,
-			", opts ...ClientOption) ",
-			file.QualifiedGoIdent(codegen.ErrorIdent),
-			" {",
-		)
-		file.P(
-			"\treqEnvelope, err := ",
-			file.QualifiedGoIdent(codegen.SOAPNewEnvelopeIdent),
-			"(",
-			file.QualifiedGoIdent(codegen.SOAPWithBodyIdent),
-			"(req))",
-		)
-		file.P("\tif err != nil {")
-		file.P(
-			"\t\treturn ",
-			file.QualifiedGoIdent(codegen.FmtErrorfIdent),
-			"(\"failed to create SOAP envelope: %w\", err)",
-		)
-		file.P("\t}")
-		if soapAction != "" {
-			file.P("\t_, err = c.Call(ctx, \"", soapAction, "\", reqEnvelope, opts...)")
-		} else {
-			file.P("\t_, err = c.Call(ctx, \"\", reqEnvelope, opts...)")
-		}
-		file.P("\tif err != nil {")
-		file.P("\t\treturn ", file.QualifiedGoIdent(codegen.FmtErrorfIdent), "(\"SOAP call failed: %w\", err)")
-		file.P("\t}")
+	// SOAP with Attachments: mime:multipartRelated bindings carry extra MIME parts
+	inputAttachments, hasInputAttachments := g.getMIMEAttachments(operation.Name, binding, true)
+	outputAttachments, hasOutputAttachments := g.getMIMEAttachments(operation.Name, binding, false)
+	hasOutputAttachments = hasOutputAttachments && !isOneWay
+	if hasInputAttachments {
+		file.P("//")
+		file.P("// Request attachments (multipart/related)", describeMIMEContents(inputAttachments), ".")
+	}
+	if hasOutputAttachments {
+		file.P("//")
+		file.P("// Response attachments (multipart/related)", describeMIMEContents(outputAttachments), ".")
+	}
+
+	// Declared soap:header blocks are sent and read with per-call options
+	if headerTypes := g.getSOAPHeaderTypes(operation.Name, binding, false); len(headerTypes) > 0 {
+		file.P("//")
+		file.P("// Request headers (send with soap.WithHeader): ", strings.Join(headerTypes, ", "), ".")
+	}
+	if headerTypes := g.getSOAPHeaderTypes(operation.Name, binding, true); len(headerTypes) > 0 {
+		file.P("//")
+		file.P("// Response headers (read with soap.WithResponseHeader): ", strings.Join(headerTypes, ", "), ".")
+	}
+
+	// Build the method signature: one-way operations return only an error
+	params := "ctx " + file.QualifiedGoIdent(codegen.ContextIdent) + ", req *" + inputType
+	if hasInputAttachments {
+		params += ", attachments []" + file.QualifiedGoIdent(codegen.SOAPAttachmentIdent)
+	}
+	params += ", opts ...ClientOption"
+	var results, zeroResults []string
+	if !isOneWay {
+		results = append(results, "*"+outputType)
+		zeroResults = append(zeroResults, "nil")
+	}
+	if hasOutputAttachments {
+		results = append(results, "[]"+file.QualifiedGoIdent(codegen.SOAPAttachmentIdent))
+		zeroResults = append(zeroResults, "nil")
+	}
+	results = append(results, file.QualifiedGoIdent(codegen.ErrorIdent))
+	resultList := results[0]
+	if len(results) > 1 {
+		resultList = "(" + strings.Join(results, ", ") + ")"
+	}
+	errPrefix := strings.Join(append(zeroResults, ""), ", ")
+
+	// Declared faults are decoded into their error types
+	faults := g.getOperationFaultTypes(operation)
+	callErr := "err"
+	if len(faults) > 0 {
+		callErr = "decode" + methodName + "Fault(err)"
+	}
+
+	file.P("func (c *Client) ", methodName, "(", params, ") ", resultList, " {")
+	g.generateOperationOptions(file, operation)
+	g.generateNewEnvelopeCall(file)
+	file.P("\tif err != nil {")
+	file.P(
+		"\t\treturn ",
+		errPrefix,
+		file.QualifiedGoIdent(codegen.FmtErrorfIdent),
+		"(\"failed to create SOAP envelope: %w\", err)",
+	)
+	file.P("\t}")
+	if hasInputAttachments {
+		file.P("\treqEnvelope.Attachments = attachments")
+	}
+	// Responses are decoded while they are read, without buffering the body
+	switch {
+	case isOneWay:
+		file.P("\t_, err = c.Call(ctx, \"", soapAction, "\", reqEnvelope, opts...)")
+	case hasOutputAttachments:
+		file.P("\tvar result ", outputType)
+		file.P("\trespEnvelope, err := c.CallDecode(ctx, \"", soapAction, "\", reqEnvelope, &result, opts...)")
+	default:
+		file.P("\tvar result ", outputType)
+		file.P("\t_, err = c.CallDecode(ctx, \"", soapAction, "\", reqEnvelope, &result, opts...)")
+	}
+	file.P("\tif err != nil {")
+	file.P("\t\treturn ", errPrefix, file.QualifiedGoIdent(codegen.FmtErrorfIdent), "(\"SOAP call failed: %w\", ", callErr, ")")
+	file.P("\t}")
+	switch {
+	case isOneWay:
 		file.P("\treturn nil")
+	case hasOutputAttachments:
+		file.P("\treturn &result, respEnvelope.Attachments, nil")
+	default:
+		file.P("\treturn &result, nil")
+	}
+	file.P("}")
+	file.P()
+
+	// Attachments are per call, so batches only cover plain operations
+	if !hasInputAttachments && !hasOutputAttachments {
+		g.generateBatchMethod(file, methodName, inputType, outputType, isOneWay)
+	}
+
+	if len(faults) > 0 {
+		g.generateFaultDecoder(file, methodName, faults)
+	}
+
+	return nil
+}
+
+// generateBatchMethod generates a method executing an operation for many requests
+// with soap.Batch. One-way operations have empty results.
+func (g *Generator) generateBatchMethod(file *codegen.File, methodName, inputType, outputType string, isOneWay bool) {
+	resultType := "*" + outputType
+	if isOneWay {
+		resultType = "struct{}"
+	}
+	ctxType := file.QualifiedGoIdent(codegen.ContextIdent)
+	file.P("// ", methodName, "Batch executes ", methodName, " for each request, with the concurrency and")
+	file.P("// error handling of the batch options. Results are in the order of the requests.")
+	file.P("// Client options are applied to each call with soap.WithBatchCallOptions.")
+	file.P(
+		"func (c *Client) ", methodName, "Batch(ctx ", ctxType, ", reqs []*", inputType,
+		", opts ...", file.QualifiedGoIdent(codegen.SOAPBatchOptionIdent), ") []",
+		file.QualifiedGoIdent(codegen.SOAPBatchResultIdent), "[", resultType, "] {",
+	)
+	file.P("\tcallOpts := ", file.QualifiedGoIdent(codegen.SOAPBatchCallOptionsIdent), "(opts...)")
+	file.P(
+		"\treturn ", file.QualifiedGoIdent(codegen.SOAPBatchIdent), "(ctx, reqs, func(ctx ", ctxType,
+		", req *", inputType, ") (", resultType, ", ", file.QualifiedGoIdent(codegen.ErrorIdent), ") {",
+	)
+	if isOneWay {
+		file.P("\t\treturn struct{}{}, c.", methodName, "(ctx, req, callOpts...)")
 	} else {
-		// Request-response operation: return response and error
-		file.P(
-			"func (c *Client) ",
-			methodName,
-			"(ctx ",
-			file.QualifiedGoIdent(codegen.ContextIdent),
-			", req *",
-			inputType,
-			", opts ...ClientOption) (*",
-			outputType,
-			", ",
-			file.QualifiedGoIdent(codegen.ErrorIdent),
-			") {",
-		)
+		file.P("\t\treturn c.", methodName, "(ctx, req, callOpts...)")
+	}
+	file.P("\t}, opts...)")
+	file.P("}")
+	file.P()
+}
+
+// generateOperationOptions generates the default call options configured for an operation
+func (g *Generator) generateOperationOptions(file *codegen.File, operation *wsdl.Operation) {
+	var defaults []string
+	if slices.Contains(g.config.MTOMOperations, operation.Name) {
+		defaults = append(defaults, file.QualifiedGoIdent(codegen.SOAPWithMTOMIdent)+"(true)")
+		// Only the binary elements of the schema are optimized
+		if paths := g.getXOPElementPaths(operation); len(paths) > 0 {
+			quoted := make([]string, 0, len(paths))
+			for _, path := range paths {
+				quoted = append(quoted, strconv.Quote(path))
+			}
+			defaults = append(defaults, file.QualifiedGoIdent(codegen.SOAPWithXOPElementsIdent)+"("+strings.Join(quoted, ", ")+")")
+		}
+	}
+	if slices.Contains(g.config.IdempotentOperations, operation.Name) {
+		defaults = append(defaults, file.QualifiedGoIdent(codegen.SOAPWithIdempotentIdent)+"(true)")
+	}
+	if len(defaults) > 0 {
+		file.P("\topts = append([]ClientOption{", strings.Join(defaults, ", "), "}, opts...)")
+	}
+}
+
+// generateNewEnvelopeCall generates the creation of the request envelope
+func (g *Generator) generateNewEnvelopeCall(file *codegen.File) {
+	if g.getSOAPVersion() == soap12 {
 		file.P(
 			"\treqEnvelope, err := ",
 			file.QualifiedGoIdent(codegen.SOAPNewEnvelopeIdent),
 			"(",
 			file.QualifiedGoIdent(codegen.SOAPWithBodyIdent),
-			"(req))",
-		)
-		file.P("\tif err != nil {")
-		file.P(
-			"\t\treturn nil, ",
-			file.QualifiedGoIdent(codegen.FmtErrorfIdent),
-			"(\"failed to create SOAP envelope: %w\", err)",
+			"(req), ",
+			file.QualifiedGoIdent(codegen.SOAPWithVersionIdent),
+			"(",
+			file.QualifiedGoIdent(codegen.SOAPVersion12Ident),
+			"))",
 		)
-		file.P("\t}")
-		if soapAction != "" {
-			file.P("\trespEnvelope, err := c.Call(ctx, \"", soapAction, "\", reqEnvelope, opts...)")
-		} else {
-			file.P("\trespEnvelope, err := c.Call(ctx, \"\", reqEnvelope, opts...)")
+		return
+	}
+	file.P(
+		"\treqEnvelope, err := ",
+		file.QualifiedGoIdent(codegen.SOAPNewEnvelopeIdent),
+		"(",
+		file.QualifiedGoIdent(codegen.SOAPWithBodyIdent),
+		"(req))",
+	)
+}
+
+// getMIMEAttachments returns the mime:content parts declared for the input or output
+// of an operation, and whether the binding declares a mime:multipartRelated message.
+// The MIME part carrying the soap:body is not an attachment and is excluded.
+func (g *Generator) getMIMEAttachments(
+	operationName string,
+	binding *wsdl.Binding,
+	input bool,
+) ([]*wsdl.MIMEContent, bool) {
+	for _, bindingOp := range binding.BindingOperations {
+		if bindingOp.Name != operationName {
+			continue
 		}
-		file.P("\tif err != nil {")
-		file.P("\t\treturn nil, ", file.QualifiedGoIdent(codegen.FmtErrorfIdent), "(\"SOAP call failed: %w\", err)")
-		file.P("\t}")
-		file.P("\tvar result ", outputType)
-		file.P(
-			"\tif err := ",
-			file.QualifiedGoIdent(codegen.XMLUnmarshalIdent),
-			"(respEnvelope.Body.Content, &result); err != nil {",
-		)
-		file.P(
-			"\t\treturn nil, ",
-			file.QualifiedGoIdent(codegen.FmtErrorfIdent),
-			"(\"failed to unmarshal response body: %w\", err)",
-		)
-		file.P("\t}")
-		file.P("\treturn &result, nil")
+		body := bindingOp.Output
+		if input {
+			body = bindingOp.Input
+		}
+		if body == nil || body.MIMEMultipartRelated == nil {
+			return nil, false
+		}
+		var contents []*wsdl.MIMEContent
+		for _, part := range body.MIMEMultipartRelated.Parts {
+			if part.SOAP11Body != nil {
+				continue
+			}
+			contents = append(contents, part.MIMEContent...)
+		}
+		return contents, true
 	}
-	file.P("}")
-	file.P()
+	return nil, false
+}
 
-	return nil
+// describeMIMEContents describes the declared attachment parts and their content types for documentation.
+func describeMIMEContents(contents []*wsdl.MIMEContent) string {
+	var parts []string
+	types := make(map[string][]string)
+	for _, content := range contents {
+		if !slices.Contains(parts, content.Part) {
+			parts = append(parts, content.Part)
+		}
+		if content.Type != "" && !slices.Contains(types[content.Part], content.Type) {
+			types[content.Part] = append(types[content.Part], content.Type)
+		}
+	}
+	descriptions := make([]string, 0, len(parts))
+	for _, part := range parts {
+		description := part
+		if len(types[part]) > 0 {
+			description += " (" + strings.Join(types[part], ", ") + ")"
+		}
+		descriptions = append(descriptions, strings.TrimSpace(description))
+	}
+	if len(descriptions) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(descriptions, ", ")
 }
 
 // getOperationTypes determines the input and output types for an operation
@@ -347,35 +582,87 @@ func (g *Generator) getOperationTypes(operation *wsdl.Operation) (inputType, out
 
 // getMessageElementType gets the Go type name for a message element
 func (g *Generator) getMessageElementType(messageName string) (string, error) {
+	elementName := g.getMessageElementName(messageName)
+	if elementName == "" {
+		return "", fmt.Errorf("message %s not found", messageName)
+	}
+	// Use consistent type naming based on binding style
+	return g.getConsistentTypeName(elementName, g.getBindingStyle()), nil
+}
+
+// getMessageElementName gets the name of the element of the first element part
+// of a message, without namespace prefix, or "" if there is none
+func (g *Generator) getMessageElementName(messageName string) string {
 	// Remove namespace prefix if present
 	if colonIdx := strings.LastIndex(messageName, ":"); colonIdx != -1 {
 		messageName = messageName[colonIdx+1:]
 	}
-
-	// Get the binding style for consistent naming
-	bindingStyle := g.getBindingStyle()
-
-	// Find the message definition
 	for _, message := range g.definitions.Messages {
-		if message.Name == messageName {
-			// Get the element from the message part
-			if len(message.Parts) > 0 {
-				part := message.Parts[0]
-				if part.Element != "" {
-					// Extract element name
-					elementName := part.Element
-					if colonIdx := strings.LastIndex(elementName, ":"); colonIdx != -1 {
-						elementName = elementName[colonIdx+1:]
-					}
-
-					// Use consistent type naming based on binding style
-					return g.getConsistentTypeName(elementName, bindingStyle), nil
+		if message.Name != messageName {
+			continue
+		}
+		// With SOAP with Attachments the remaining parts are transmitted as
+		// MIME attachments
+		for _, part := range message.Parts {
+			if part.Element != "" {
+				elementName := part.Element
+				if colonIdx := strings.LastIndex(elementName, ":"); colonIdx != -1 {
+					elementName = elementName[colonIdx+1:]
 				}
+				return elementName
+			}
+		}
+	}
+	return ""
+}
+
+// getSOAPHeaderTypes returns the Go types of the soap:header blocks declared
+// for the input of an operation, or its output.
+func (g *Generator) getSOAPHeaderTypes(operationName string, binding *wsdl.Binding, output bool) []string {
+	var result []string
+	for _, bindingOp := range binding.BindingOperations {
+		body := bindingOp.Input
+		if output {
+			body = bindingOp.Output
+		}
+		if bindingOp.Name != operationName || body == nil {
+			continue
+		}
+		headers := body.SOAP11Headers
+		if len(headers) == 0 {
+			headers = body.SOAP12Headers
+		}
+		for _, header := range headers {
+			typeName := g.getMessagePartElementType(header.Message, header.Part)
+			if typeName != "" && !slices.Contains(result, typeName) {
+				result = append(result, typeName)
 			}
 		}
 	}
+	return result
+}
 
-	return "", fmt.Errorf("message %s not found", messageName)
+// getMessagePartElementType gets the Go type name for the element of a named message part
+func (g *Generator) getMessagePartElementType(messageName, partName string) string {
+	if colonIdx := strings.LastIndex(messageName, ":"); colonIdx != -1 {
+		messageName = messageName[colonIdx+1:]
+	}
+	for _, message := range g.definitions.Messages {
+		if message.Name != messageName {
+			continue
+		}
+		for _, part := range message.Parts {
+			if part.Name != partName || part.Element == "" {
+				continue
+			}
+			elementName := part.Element
+			if colonIdx := strings.LastIndex(elementName, ":"); colonIdx != -1 {
+				elementName = elementName[colonIdx+1:]
+			}
+			return g.getConsistentTypeName(elementName, g.getBindingStyle())
+		}
+	}
+	return ""
 }
 
 // getSOAPActionForOperation gets the SOAP action for an operation from binding
@@ -397,4 +684,7 @@ func (g *Generator) getSOAPActionForOperation(operationName string, binding *wsd
 func (g *Generator) generateHelperFunctions(file *codegen.File) {
 	// Note: SOAP envelope types are now provided by the public API
 	// No need to generate private types anymore
+
+	// Declared WSDL faults are returned as typed errors
+	g.generateFaultTypes(file)
 }
@@ -9,6 +9,24 @@ type BindingStyle struct {
 	EncodingStyle string // optional encoding style URI
 }
 
+// soapVersion identifies the SOAP version of the bindings used for client generation
+type soapVersion string
+
+const (
+	soap11 soapVersion = "1.1"
+	soap12 soapVersion = "1.2"
+)
+
+// getSOAPVersion determines the SOAP version of the bindings used for client generation
+func (g *Generator) getSOAPVersion() soapVersion {
+	// getSOAPBindings only returns SOAP 1.2 bindings when no SOAP 1.1 binding exists
+	soapBindings := g.getSOAPBindings()
+	if len(soapBindings) > 0 && soapBindings[0].SOAP11Binding == nil {
+		return soap12
+	}
+	return soap11
+}
+
 // getBindingStyle determines the SOAP binding style for the given WSDL
 func (g *Generator) getBindingStyle() BindingStyle {
 	// Find the first SOAP binding (prefer SOAP 1.1)
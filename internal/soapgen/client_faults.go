@@ -0,0 +1,183 @@
+package soapgen
+
+import (
+	"encoding/xml"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/way-platform/soap-go/internal/codegen"
+	"github.com/way-platform/soap-go/wsdl"
+)
+
+// faultType describes the Go error type generated for a WSDL fault message
+type faultType struct {
+	element     xml.Name // qualified name of the fault detail element
+	elementName string   // name of the fault detail element, without namespace prefix
+	errorName   string   // Go name of the generated error type
+	detailType  string   // Go type of the fault detail element
+}
+
+// getFaultTypes returns the error types for the fault detail elements declared
+// by the operations of the SOAP bindings, in declaration order, one per detail
+// element. Faults whose message has no element part are skipped, since their
+// detail cannot be decoded into a generated type.
+//
+// Error types are named after the detail element with the suffix Fault. If
+// that name is taken by a schema type or an earlier fault type, the suffix
+// Error is used instead, and then a numeric suffix, so that names do not
+// depend on anything but the declaration order.
+func (g *Generator) getFaultTypes() []faultType {
+	var result []faultType
+	seen := make(map[xml.Name]bool)
+	taken := make(map[string]bool)
+	for _, binding := range g.getSOAPBindings() {
+		portType := g.getPortTypeForBinding(binding)
+		if portType == nil {
+			continue
+		}
+		for _, operation := range portType.Operations {
+			for _, fault := range operation.Faults {
+				element := g.getMessageElementQName(fault.Message)
+				if element.Local == "" || seen[element] {
+					continue
+				}
+				seen[element] = true
+				baseName := strings.TrimSuffix(toGoName(element.Local), "Fault")
+				isTaken := func(name string) bool {
+					return taken[name] || g.isSchemaTypeName(name)
+				}
+				errorName := baseName + "Fault"
+				if isTaken(errorName) {
+					errorName = baseName + "Error"
+				}
+				for i := 1; isTaken(errorName); i++ {
+					errorName = baseName + "Fault" + strconv.Itoa(i)
+				}
+				taken[errorName] = true
+				result = append(result, faultType{
+					element:     element,
+					elementName: element.Local,
+					errorName:   errorName,
+					detailType:  g.getConsistentTypeName(element.Local, g.getBindingStyle()),
+				})
+			}
+		}
+	}
+	return result
+}
+
+// getOperationFaultTypes returns the error types for the fault messages declared
+// by an operation, in declaration order.
+func (g *Generator) getOperationFaultTypes(operation *wsdl.Operation) []faultType {
+	faultTypes := g.getFaultTypes()
+	var result []faultType
+	for _, fault := range operation.Faults {
+		element := g.getMessageElementQName(fault.Message)
+		for _, faultType := range faultTypes {
+			if faultType.element == element && !slices.Contains(result, faultType) {
+				result = append(result, faultType)
+			}
+		}
+	}
+	return result
+}
+
+// getMessageElementQName returns the qualified name of the element part of a
+// message, with the target namespace of the schema declaring it
+func (g *Generator) getMessageElementQName(messageName string) xml.Name {
+	elementName := g.getMessageElementName(messageName)
+	if elementName == "" || g.definitions.Types == nil {
+		return xml.Name{Local: elementName}
+	}
+	for _, schema := range g.definitions.Types.Schemas {
+		for _, element := range schema.Elements {
+			if element.Name == elementName {
+				return xml.Name{Space: schema.TargetNamespace, Local: elementName}
+			}
+		}
+	}
+	return xml.Name{Local: elementName}
+}
+
+// isSchemaTypeName reports whether a Go type generated from the schemas may use the given name
+func (g *Generator) isSchemaTypeName(name string) bool {
+	if g.definitions.Types == nil {
+		return false
+	}
+	bindingStyle := g.getBindingStyle()
+	for _, schema := range g.definitions.Types.Schemas {
+		for _, element := range schema.Elements {
+			if g.getConsistentTypeName(element.Name, bindingStyle) == name {
+				return true
+			}
+		}
+		for _, complexType := range schema.ComplexTypes {
+			if toGoName(complexType.Name) == name {
+				return true
+			}
+		}
+		for _, simpleType := range schema.SimpleTypes {
+			if toGoName(simpleType.Name) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateFaultTypes generates an error type for each declared fault message
+func (g *Generator) generateFaultTypes(file *codegen.File) {
+	for _, fault := range g.getFaultTypes() {
+		file.P("// ", fault.errorName, " is returned when a call fails with a SOAP fault carrying")
+		if g.config.GenerateServer {
+			file.P("// the ", fault.elementName, " fault detail. Services return it to send that fault.")
+		} else {
+			file.P("// the ", fault.elementName, " fault detail.")
+		}
+		file.P("type ", fault.errorName, " struct {")
+		file.P("\t// Detail is the decoded fault detail.")
+		file.P("\tDetail *", fault.detailType)
+		file.P()
+		file.P("\t// Err is the underlying SOAP error.")
+		file.P("\tErr *", file.QualifiedGoIdent(codegen.SOAPErrorIdent))
+		file.P("}")
+		file.P()
+		file.P("// Error implements the error interface.")
+		file.P("func (e ", fault.errorName, ") Error() string {")
+		file.P("\tif e.Err == nil {")
+		file.P("\t\treturn \"SOAP fault with ", fault.elementName, " detail\"")
+		file.P("\t}")
+		file.P("\treturn e.Err.Error()")
+		file.P("}")
+		file.P()
+		file.P("// Unwrap returns the underlying SOAP error, if any.")
+		file.P("func (e ", fault.errorName, ") Unwrap() error {")
+		file.P("\tif e.Err == nil {")
+		file.P("\t\treturn nil")
+		file.P("\t}")
+		file.P("\treturn e.Err")
+		file.P("}")
+		file.P()
+	}
+}
+
+// generateFaultDecoder generates a function converting the SOAP faults declared
+// by an operation into their error types
+func (g *Generator) generateFaultDecoder(file *codegen.File, methodName string, faults []faultType) {
+	file.P("// decode", methodName, "Fault converts the SOAP faults declared by ", methodName)
+	file.P("// into their error types. Other errors are returned unchanged.")
+	file.P("func decode", methodName, "Fault(err error) error {")
+	file.P("\tvar soapErr *", file.QualifiedGoIdent(codegen.SOAPErrorIdent))
+	file.P("\tif !", file.QualifiedGoIdent(codegen.ErrorsAsIdent), "(err, &soapErr) || soapErr.Fault == nil || soapErr.Fault.Detail == nil {")
+	file.P("\t\treturn err")
+	file.P("\t}")
+	for _, fault := range faults {
+		file.P("\tif detail := new(", fault.detailType, "); soapErr.Fault.DecodeDetail(detail) == nil {")
+		file.P("\t\treturn ", fault.errorName, "{Detail: detail, Err: soapErr}")
+		file.P("\t}")
+	}
+	file.P("\treturn err")
+	file.P("}")
+	file.P()
+}
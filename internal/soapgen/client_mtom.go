@@ -0,0 +1,155 @@
+package soapgen
+
+import (
+	"slices"
+
+	"github.com/way-platform/soap-go/wsdl"
+	"github.com/way-platform/soap-go/xsd"
+)
+
+// getXOPElementPaths returns the paths from the body of the binary elements of
+// the request of an operation, the elements that may be XOP-optimized with MTOM
+func (g *Generator) getXOPElementPaths(operation *wsdl.Operation) []string {
+	if operation.Input == nil || g.definitions.Types == nil {
+		return nil
+	}
+	elementName := g.getMessageElementName(operation.Input.Message)
+	if elementName == "" {
+		return nil
+	}
+	for i := range g.definitions.Types.Schemas {
+		ctx := newSchemaContext(&g.definitions.Types.Schemas[i], g)
+		if element := ctx.resolveElementRef(elementName); element != nil {
+			var paths []string
+			collectXOPElementPaths(ctx, element, "", make(map[string]bool), &paths)
+			return paths
+		}
+	}
+	return nil
+}
+
+// collectXOPElementPaths adds the paths of the elements of type xsd:base64Binary
+// found in an element. Named complex types being visited are skipped, so that
+// recursive types end.
+func collectXOPElementPaths(ctx *SchemaContext, element *xsd.Element, parent string, visiting map[string]bool, paths *[]string) {
+	if element.Ref != "" {
+		if element = ctx.resolveElementRef(element.Ref); element == nil {
+			return
+		}
+	}
+	path := element.Name
+	if parent != "" {
+		path = parent + "/" + element.Name
+	}
+	addPath := func() {
+		if !slices.Contains(*paths, path) {
+			*paths = append(*paths, path)
+		}
+	}
+	switch {
+	case element.Type != "":
+		if complexType := ctx.resolveComplexType(element.Type); complexType != nil {
+			typeName := extractLocalName(element.Type)
+			if !visiting[typeName] {
+				visiting[typeName] = true
+				collectComplexTypeXOPPaths(ctx, complexType, path, visiting, paths, addPath)
+				delete(visiting, typeName)
+			}
+		} else if isBase64BinaryType(element.Type, ctx) {
+			addPath()
+		}
+	case element.SimpleType != nil:
+		if element.SimpleType.Restriction != nil && isBase64BinaryType(element.SimpleType.Restriction.Base, ctx) {
+			addPath()
+		}
+	case element.ComplexType != nil:
+		collectComplexTypeXOPPaths(ctx, element.ComplexType, path, visiting, paths, addPath)
+	}
+}
+
+// collectComplexTypeXOPPaths adds the paths of the binary elements of a complex
+// type, calling addPath if its own content is binary
+func collectComplexTypeXOPPaths(
+	ctx *SchemaContext,
+	complexType *xsd.ComplexType,
+	path string,
+	visiting map[string]bool,
+	paths *[]string,
+	addPath func(),
+) {
+	collect := func(elements []xsd.Element) {
+		for i := range elements {
+			collectXOPElementPaths(ctx, &elements[i], path, visiting, paths)
+		}
+	}
+	var collectSequence func(*xsd.Sequence)
+	var collectChoice func(*xsd.Choice)
+	collectSequence = func(sequence *xsd.Sequence) {
+		if sequence == nil {
+			return
+		}
+		collect(sequence.Elements)
+		for i := range sequence.Sequences {
+			collectSequence(&sequence.Sequences[i])
+		}
+		for i := range sequence.Choices {
+			collectChoice(&sequence.Choices[i])
+		}
+	}
+	collectChoice = func(choice *xsd.Choice) {
+		if choice == nil {
+			return
+		}
+		collect(choice.Elements)
+		for i := range choice.Sequences {
+			collectSequence(&choice.Sequences[i])
+		}
+		for i := range choice.Choices {
+			collectChoice(&choice.Choices[i])
+		}
+	}
+	collectSequence(complexType.Sequence)
+	collectChoice(complexType.Choice)
+	if complexType.All != nil {
+		collect(complexType.All.Elements)
+	}
+	if content := complexType.ComplexContent; content != nil && content.Extension != nil {
+		extension := content.Extension
+		if base := ctx.resolveComplexType(extension.Base); base != nil {
+			typeName := extractLocalName(extension.Base)
+			if !visiting[typeName] {
+				visiting[typeName] = true
+				collectComplexTypeXOPPaths(ctx, base, path, visiting, paths, addPath)
+				delete(visiting, typeName)
+			}
+		}
+		collectSequence(extension.Sequence)
+		collectChoice(extension.Choice)
+		if extension.All != nil {
+			collect(extension.All.Elements)
+		}
+	}
+	if content := complexType.SimpleContent; content != nil && content.Extension != nil &&
+		isBase64BinaryType(content.Extension.Base, ctx) {
+		addPath()
+	}
+}
+
+// isBase64BinaryType reports whether an XSD type is xsd:base64Binary or a
+// restriction of it without enumerations
+func isBase64BinaryType(typeName string, ctx *SchemaContext) bool {
+	if typeName == "" {
+		return false
+	}
+	if simpleType := ctx.resolveSimpleType(typeName); simpleType != nil {
+		restriction := simpleType.Restriction
+		return restriction != nil && len(restriction.Enumerations) == 0 && isBase64BinaryType(restriction.Base, ctx)
+	}
+	return xsd.ParseType(typeName) == xsd.Base64Binary
+}
+
+// useBase64BinaryType reports whether xsd:base64Binary maps to
+// soap.Base64Binary, which MTOM needs to send its elements as base64 text
+func (g *Generator) useBase64BinaryType() bool {
+	return g.config.Base64BinaryType || len(g.config.MTOMOperations) > 0
+}
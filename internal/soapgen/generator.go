@@ -10,8 +10,19 @@ import (
 
 // Config holds configuration for code generation
 type Config struct {
-	PackageName    string
-	GenerateClient bool // Whether to generate SOAP client code
+	PackageName          string
+	GenerateClient       bool                 // Whether to generate SOAP client code
+	GenerateServer       bool                 // Whether to generate a SOAP service interface and server handler
+	MTOMOperations       []string             // Operations whose requests are sent as MTOM/XOP packages, optimizing their xsd:base64Binary elements
+	IdempotentOperations []string             // Operations that are safe to retry on server errors and timeouts
+	RateLimits           map[string]RateLimit // Client-side rate limits of operations, by operation name
+	Base64BinaryType     bool                 // Map xsd:base64Binary to soap.Base64Binary instead of []byte; implied by MTOMOperations
+}
+
+// RateLimit is a client-side rate limit of an operation
+type RateLimit struct {
+	PerSecond float64 // Average number of requests per second
+	Burst     int     // Maximum number of requests at once; zero means 1
 }
 
 // Generator generates Go code from WSDL definitions
@@ -63,6 +74,17 @@ func (g *Generator) Generate() error {
 		}
 	}
 
+	// Generate server file if requested
+	if g.config.GenerateServer {
+		serverFile, err := g.generateServerFile(g.config.PackageName, "server.go")
+		if err != nil {
+			return fmt.Errorf("failed to generate server file: %w", err)
+		}
+		if serverFile != nil {
+			g.files = append(g.files, serverFile)
+		}
+	}
+
 	return nil
 }
 
@@ -64,6 +64,8 @@ func convertToQualifiedType(rawType string, g *codegen.File) string {
 		return g.QualifiedGoIdent(codegen.IntIdent)
 	case "[]byte":
 		return "[]" + g.QualifiedGoIdent(codegen.ByteIdent)
+	case "soap.Base64Binary":
+		return g.QualifiedGoIdent(codegen.SOAPBase64BinaryIdent)
 	default:
 		return rawType
 	}
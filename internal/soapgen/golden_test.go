@@ -1,6 +1,7 @@
 package soapgen
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/fs"
@@ -37,10 +38,11 @@ func TestGoldenFiles(t *testing.T) {
 }
 
 type testCase struct {
-	name      string
-	dir       string
-	wsdlFile  string
-	errorFile string // path to error.txt if it exists
+	name       string
+	dir        string
+	wsdlFile   string
+	errorFile  string // path to error.txt if it exists
+	configFile string // path to config.json if it exists
 }
 
 func discoverTestCases(testdataDir string) ([]testCase, error) {
@@ -66,11 +68,18 @@ func discoverTestCases(testdataDir string) ([]testCase, error) {
 					errorFile = "" // error file doesn't exist
 				}
 
+				// Check if generator config file exists
+				configFile := filepath.Join(path, "config.json")
+				if _, err := os.Stat(configFile); err != nil {
+					configFile = "" // config file doesn't exist
+				}
+
 				testCases = append(testCases, testCase{
-					name:      d.Name(),
-					dir:       path,
-					wsdlFile:  wsdlFile,
-					errorFile: errorFile,
+					name:       d.Name(),
+					dir:        path,
+					wsdlFile:   wsdlFile,
+					errorFile:  errorFile,
+					configFile: configFile,
 				})
 			}
 		}
@@ -104,10 +113,21 @@ func runGoldenTest(t *testing.T, tc testCase) {
 	}
 
 	// Create generator with the test case directory as package name
-	generator := NewGenerator(defs, Config{
+	config := Config{
 		PackageName:    tc.name,
 		GenerateClient: true, // Enable client generation for golden tests
-	})
+	}
+	// Apply additional generator settings from config.json
+	if tc.configFile != "" {
+		configData, err := os.ReadFile(tc.configFile)
+		if err != nil {
+			t.Fatalf("Failed to read config file: %v", err)
+		}
+		if err := json.Unmarshal(configData, &config); err != nil {
+			t.Fatalf("Failed to parse config file: %v", err)
+		}
+	}
+	generator := NewGenerator(defs, config)
 
 	// Generate code
 	err = generator.Generate()
@@ -0,0 +1,195 @@
+package soapgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/way-platform/soap-go/internal/codegen"
+	"github.com/way-platform/soap-go/wsdl"
+)
+
+// serverOperation is a portType operation served by the generated server
+type serverOperation struct {
+	operation *wsdl.Operation
+	binding   *wsdl.Binding
+}
+
+// generateServerFile generates a Go file with the service interface and its HTTP handler
+func (g *Generator) generateServerFile(packageName, filename string) (*codegen.File, error) {
+	// Collect the operations of the SOAP bindings, like the client
+	var operations []serverOperation
+	for _, binding := range g.getSOAPBindings() {
+		portType := g.getPortTypeForBinding(binding)
+		if portType == nil {
+			continue
+		}
+		for i := range portType.Operations {
+			operations = append(operations, serverOperation{operation: &portType.Operations[i], binding: binding})
+		}
+	}
+	if len(operations) == 0 {
+		// No operations found, don't generate server file
+		return nil, nil
+	}
+
+	file := codegen.NewFile(filename, packageName)
+
+	// Set custom package name for soap-go to use "soap" instead of "soapgo"
+	file.SetPackageName("github.com/way-platform/soap-go", "soap")
+
+	// Add package declaration
+	file.P("package ", packageName)
+	file.P()
+
+	// Generate service interface
+	if err := g.generateServiceInterface(file, operations); err != nil {
+		return nil, err
+	}
+
+	// Generate registration functions
+	if err := g.generateRegisterServiceFunction(file, operations); err != nil {
+		return nil, err
+	}
+	g.generateNewHandlerFunction(file)
+	g.generateFaultEncoder(file)
+
+	// Without a client, the fault error types are declared with the server
+	if !g.config.GenerateClient {
+		file.P()
+		g.generateFaultTypes(file)
+	}
+
+	return file, nil
+}
+
+// generateServiceInterface generates the interface with one method per operation
+func (g *Generator) generateServiceInterface(file *codegen.File, operations []serverOperation) error {
+	file.P("// Service is the interface implemented by servers of the SOAP service.")
+	file.P("//")
+	if len(g.getFaultTypes()) > 0 {
+		file.P("// Methods return one of the fault errors, such as ", g.getFaultTypes()[0].errorName, ", to send a")
+		file.P("// declared fault with its detail, or a *soap.Fault to send a specific SOAP")
+		file.P("// fault; other errors are sent as server faults.")
+	} else {
+		file.P("// Methods return a *soap.Fault to send a specific SOAP fault; other errors")
+		file.P("// are sent as server faults.")
+	}
+	file.P("type Service interface {")
+	for i, op := range operations {
+		inputType, outputType, err := g.getOperationTypes(op.operation)
+		if err != nil {
+			return fmt.Errorf("failed to get types for operation %s: %w", op.operation.Name, err)
+		}
+		methodName := toGoName(op.operation.Name)
+		if i > 0 {
+			file.P()
+		}
+		if op.operation.Documentation != "" {
+			doc := strings.TrimSpace(op.operation.Documentation)
+			doc = strings.ReplaceAll(doc, "\n", " ")
+			file.P("\t// ", methodName, " ", doc)
+		} else {
+			file.P("\t// ", methodName, " handles the ", op.operation.Name, " SOAP operation.")
+		}
+		params := "ctx " + file.QualifiedGoIdent(codegen.ContextIdent) + ", req *" + inputType
+		if op.operation.Output == nil {
+			file.P("\t", methodName, "(", params, ") ", file.QualifiedGoIdent(codegen.ErrorIdent))
+		} else {
+			file.P("\t", methodName, "(", params, ") (*", outputType, ", ", file.QualifiedGoIdent(codegen.ErrorIdent), ")")
+		}
+	}
+	file.P("}")
+	file.P()
+	return nil
+}
+
+// generateRegisterServiceFunction generates the function registering the operations on a soap.Server
+func (g *Generator) generateRegisterServiceFunction(file *codegen.File, operations []serverOperation) error {
+	file.P("// RegisterService registers the operations of the service on a SOAP server.")
+	file.P("// Requests are dispatched by SOAPAction, or by their request element when")
+	file.P("// the action is missing.")
+	file.P("func RegisterService(server *", file.QualifiedGoIdent(codegen.SOAPServerIdent), ", service Service) {")
+	for _, op := range operations {
+		inputType, outputType, err := g.getOperationTypes(op.operation)
+		if err != nil {
+			return fmt.Errorf("failed to get types for operation %s: %w", op.operation.Name, err)
+		}
+		methodName := toGoName(op.operation.Name)
+		soapAction := g.getSOAPActionForOperation(op.operation.Name, op.binding)
+		hasFaults := len(g.getOperationFaultTypes(op.operation)) > 0
+		switch {
+		case op.operation.Output == nil:
+			// One-way operations are acknowledged without a response envelope
+			file.P(
+				"\t", file.QualifiedGoIdent(codegen.SOAPHandleIdent), "(server, \"", soapAction, "\", func(ctx ",
+				file.QualifiedGoIdent(codegen.ContextIdent), ", req *", inputType, ") (*struct{}, error) {",
+			)
+			if hasFaults {
+				file.P("\t\treturn nil, encodeFault(service.", methodName, "(ctx, req))")
+			} else {
+				file.P("\t\treturn nil, service.", methodName, "(ctx, req)")
+			}
+			file.P("\t})")
+		case hasFaults:
+			// Declared faults are sent with their detail
+			file.P(
+				"\t", file.QualifiedGoIdent(codegen.SOAPHandleIdent), "(server, \"", soapAction, "\", func(ctx ",
+				file.QualifiedGoIdent(codegen.ContextIdent), ", req *", inputType, ") (*", outputType, ", error) {",
+			)
+			file.P("\t\tresp, err := service.", methodName, "(ctx, req)")
+			file.P("\t\treturn resp, encodeFault(err)")
+			file.P("\t})")
+		default:
+			file.P("\t", file.QualifiedGoIdent(codegen.SOAPHandleIdent), "(server, \"", soapAction, "\", service.", methodName, ")")
+		}
+	}
+	file.P("}")
+	file.P()
+	return nil
+}
+
+// generateNewHandlerFunction generates the function returning an http.Handler for a service
+func (g *Generator) generateNewHandlerFunction(file *codegen.File) {
+	file.P("// NewHandler returns an HTTP handler serving the service.")
+	file.P(
+		"func NewHandler(service Service, opts ...", file.QualifiedGoIdent(codegen.SOAPServerOptionIdent), ") ",
+		file.QualifiedGoIdent(codegen.HTTPHandlerIdent), " {",
+	)
+	file.P("\tserver := ", file.QualifiedGoIdent(codegen.SOAPNewServerIdent), "(opts...)")
+	file.P("\tRegisterService(server, service)")
+	file.P("\treturn server")
+	file.P("}")
+}
+
+// generateFaultEncoder generates a function converting the fault errors returned
+// by the service into SOAP faults carrying their detail
+func (g *Generator) generateFaultEncoder(file *codegen.File) {
+	faults := g.getFaultTypes()
+	if len(faults) == 0 {
+		return
+	}
+	file.P()
+	file.P("// encodeFault converts the fault errors returned by the service into server")
+	file.P("// SOAP faults carrying their detail. Other errors are returned unchanged.")
+	file.P("func encodeFault(err error) error {")
+	for _, fault := range faults {
+		file.P("\tif fault := (", fault.errorName, "{}); ", file.QualifiedGoIdent(codegen.ErrorsAsIdent), "(err, &fault) && fault.Detail != nil {")
+		file.P("\t\treturn newDetailFault(err, fault.Detail)")
+		file.P("\t}")
+	}
+	file.P("\treturn err")
+	file.P("}")
+	file.P()
+	file.P("// newDetailFault returns a server SOAP fault carrying a fault detail element.")
+	file.P("func newDetailFault(err error, detail any) error {")
+	file.P("\tcontent, marshalErr := ", file.QualifiedGoIdent(codegen.XMLMarshalIdent), "(detail)")
+	file.P("\tif marshalErr != nil {")
+	file.P("\t\treturn ", file.QualifiedGoIdent(codegen.FmtErrorfIdent), "(\"failed to encode fault detail: %w\", marshalErr)")
+	file.P("\t}")
+	file.P("\treturn &", file.QualifiedGoIdent(codegen.SOAPFaultIdent), "{")
+	file.P("\t\tFaultCode:   \"Server\",")
+	file.P("\t\tFaultString: err.Error(),")
+	file.P("\t\tDetail:      &", file.QualifiedGoIdent(codegen.SOAPDetailIdent), "{Content: content},")
+	file.P("\t}")
+	file.P("}")
+}
@@ -41,6 +41,16 @@ func (c *Client) ProcessDownloadRequest(ctx context.Context, req *DownloadReques
 	return nil
 }
 
+// ProcessDownloadRequestBatch executes ProcessDownloadRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessDownloadRequestBatch(ctx context.Context, reqs []*DownloadRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DownloadRequestWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessDownloadRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessConfigData executes the ProcessConfigData one-way SOAP operation.
 func (c *Client) ProcessConfigData(ctx context.Context, req *ConfigDataWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -53,3 +63,13 @@ func (c *Client) ProcessConfigData(ctx context.Context, req *ConfigDataWrapper,
 	}
 	return nil
 }
+
+// ProcessConfigDataBatch executes ProcessConfigData for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessConfigDataBatch(ctx context.Context, reqs []*ConfigDataWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *ConfigDataWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessConfigData(ctx, req, callOpts...)
+	}, opts...)
+}
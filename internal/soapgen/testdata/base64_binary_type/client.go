@@ -0,0 +1,53 @@
+package base64_binary_type
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/certificates"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// GetCertificate executes the GetCertificate SOAP operation.
+func (c *Client) GetCertificate(ctx context.Context, req *GetCertificateWrapper, opts ...ClientOption) (*GetCertificateResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetCertificateResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/certificates/GetCertificate", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetCertificateBatch executes GetCertificate for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetCertificateBatch(ctx context.Context, reqs []*GetCertificateWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetCertificateResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetCertificateWrapper) (*GetCertificateResponseWrapper, error) {
+		return c.GetCertificate(ctx, req, callOpts...)
+	}, opts...)
+}
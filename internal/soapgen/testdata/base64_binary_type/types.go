@@ -0,0 +1,21 @@
+package base64_binary_type
+
+import (
+	"encoding/xml"
+	soap "github.com/way-platform/soap-go"
+)
+
+// Enumeration types
+
+// GetCertificateWrapper represents the GetCertificate element
+type GetCertificateWrapper struct {
+	XMLName      xml.Name `xml:"http://example.com/certificates GetCertificate"`
+	SerialNumber []byte   `xml:"SerialNumber"`
+}
+
+// GetCertificateResponseWrapper represents the GetCertificateResponse element
+type GetCertificateResponseWrapper struct {
+	XMLName     xml.Name           `xml:"http://example.com/certificates GetCertificateResponse"`
+	Certificate soap.Base64Binary  `xml:"Certificate"`
+	Thumbprint  *soap.Base64Binary `xml:"Thumbprint,omitempty"`
+}
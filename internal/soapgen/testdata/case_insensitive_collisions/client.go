@@ -41,6 +41,16 @@ func (c *Client) TestUserRequest(ctx context.Context, req *UserRequestWrapper, o
 	return nil
 }
 
+// TestUserRequestBatch executes TestUserRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TestUserRequestBatch(ctx context.Context, reqs []*UserRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UserRequestWrapper) (struct{}, error) {
+		return struct{}{}, c.TestUserRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // TestUserRequestLower executes the TestUserRequestLower one-way SOAP operation.
 func (c *Client) TestUserRequestLower(ctx context.Context, req *UserRequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -54,6 +64,16 @@ func (c *Client) TestUserRequestLower(ctx context.Context, req *UserRequestWrapp
 	return nil
 }
 
+// TestUserRequestLowerBatch executes TestUserRequestLower for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TestUserRequestLowerBatch(ctx context.Context, reqs []*UserRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UserRequestWrapper) (struct{}, error) {
+		return struct{}{}, c.TestUserRequestLower(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // TestGetFleetResponse executes the TestGetFleetResponse one-way SOAP operation.
 func (c *Client) TestGetFleetResponse(ctx context.Context, req *GetFleetResponseWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -67,6 +87,16 @@ func (c *Client) TestGetFleetResponse(ctx context.Context, req *GetFleetResponse
 	return nil
 }
 
+// TestGetFleetResponseBatch executes TestGetFleetResponse for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TestGetFleetResponseBatch(ctx context.Context, reqs []*GetFleetResponseWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetFleetResponseWrapper) (struct{}, error) {
+		return struct{}{}, c.TestGetFleetResponse(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // TestGetFleetResponseLower executes the TestGetFleetResponseLower one-way SOAP operation.
 func (c *Client) TestGetFleetResponseLower(ctx context.Context, req *GetFleetResponseWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -79,3 +109,13 @@ func (c *Client) TestGetFleetResponseLower(ctx context.Context, req *GetFleetRes
 	}
 	return nil
 }
+
+// TestGetFleetResponseLowerBatch executes TestGetFleetResponseLower for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TestGetFleetResponseLowerBatch(ctx context.Context, reqs []*GetFleetResponseWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetFleetResponseWrapper) (struct{}, error) {
+		return struct{}{}, c.TestGetFleetResponseLower(ctx, req, callOpts...)
+	}, opts...)
+}
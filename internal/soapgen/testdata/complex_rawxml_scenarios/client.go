@@ -41,6 +41,16 @@ func (c *Client) ProcessFlexibleDocument(ctx context.Context, req *FlexibleDocum
 	return nil
 }
 
+// ProcessFlexibleDocumentBatch executes ProcessFlexibleDocument for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessFlexibleDocumentBatch(ctx context.Context, reqs []*FlexibleDocumentWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *FlexibleDocumentWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessFlexibleDocument(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessDynamicContent executes the ProcessDynamicContent one-way SOAP operation.
 func (c *Client) ProcessDynamicContent(ctx context.Context, req *DynamicContentWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -54,6 +64,16 @@ func (c *Client) ProcessDynamicContent(ctx context.Context, req *DynamicContentW
 	return nil
 }
 
+// ProcessDynamicContentBatch executes ProcessDynamicContent for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessDynamicContentBatch(ctx context.Context, reqs []*DynamicContentWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DynamicContentWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessDynamicContent(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessMixedDocument executes the ProcessMixedDocument one-way SOAP operation.
 func (c *Client) ProcessMixedDocument(ctx context.Context, req *MixedDocumentWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -67,6 +87,16 @@ func (c *Client) ProcessMixedDocument(ctx context.Context, req *MixedDocumentWra
 	return nil
 }
 
+// ProcessMixedDocumentBatch executes ProcessMixedDocument for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessMixedDocumentBatch(ctx context.Context, reqs []*MixedDocumentWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *MixedDocumentWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessMixedDocument(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessPerformanceReport executes the ProcessPerformanceReport one-way SOAP operation.
 func (c *Client) ProcessPerformanceReport(ctx context.Context, req *PerformanceReportWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -80,6 +110,16 @@ func (c *Client) ProcessPerformanceReport(ctx context.Context, req *PerformanceR
 	return nil
 }
 
+// ProcessPerformanceReportBatch executes ProcessPerformanceReport for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessPerformanceReportBatch(ctx context.Context, reqs []*PerformanceReportWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *PerformanceReportWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessPerformanceReport(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessUntypedElement executes the ProcessUntypedElement one-way SOAP operation.
 func (c *Client) ProcessUntypedElement(ctx context.Context, req *UntypedElementWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -92,3 +132,13 @@ func (c *Client) ProcessUntypedElement(ctx context.Context, req *UntypedElementW
 	}
 	return nil
 }
+
+// ProcessUntypedElementBatch executes ProcessUntypedElement for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessUntypedElementBatch(ctx context.Context, reqs []*UntypedElementWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UntypedElementWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessUntypedElement(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -2,7 +2,6 @@ package comprehensive_wrapper_naming
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,17 +34,24 @@ func (c *Client) Login(ctx context.Context, req *LoginWrapper, opts ...ClientOpt
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/test/Login", reqEnvelope, opts...)
+	var result LoginResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/test/Login", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result LoginResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// LoginBatch executes Login for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) LoginBatch(ctx context.Context, reqs []*LoginWrapper, opts ...soap.BatchOption) []soap.BatchResult[*LoginResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *LoginWrapper) (*LoginResponseWrapper, error) {
+		return c.Login(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // GetUserInfo executes the GetUserInfo one-way SOAP operation.
 func (c *Client) GetUserInfo(ctx context.Context, req *GetUserInfoWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -58,3 +64,13 @@ func (c *Client) GetUserInfo(ctx context.Context, req *GetUserInfoWrapper, opts
 	}
 	return nil
 }
+
+// GetUserInfoBatch executes GetUserInfo for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetUserInfoBatch(ctx context.Context, reqs []*GetUserInfoWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetUserInfoWrapper) (struct{}, error) {
+		return struct{}{}, c.GetUserInfo(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -2,7 +2,6 @@ package context_aware_naming
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,34 +34,48 @@ func (c *Client) ProcessUserData(ctx context.Context, req *UserDataWrapper, opts
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "urn:ProcessUserData", reqEnvelope, opts...)
+	var result UserDataWrapper
+	_, err = c.CallDecode(ctx, "urn:ProcessUserData", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result UserDataWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// ProcessUserDataBatch executes ProcessUserData for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessUserDataBatch(ctx context.Context, reqs []*UserDataWrapper, opts ...soap.BatchOption) []soap.BatchResult[*UserDataWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UserDataWrapper) (*UserDataWrapper, error) {
+		return c.ProcessUserData(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessRequest executes the ProcessRequest SOAP operation.
 func (c *Client) ProcessRequest(ctx context.Context, req *ProcessRequestWrapper, opts ...ClientOption) (*ProcessRequestWrapper, error) {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "urn:ProcessRequest", reqEnvelope, opts...)
+	var result ProcessRequestWrapper
+	_, err = c.CallDecode(ctx, "urn:ProcessRequest", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result ProcessRequestWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// ProcessRequestBatch executes ProcessRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessRequestBatch(ctx context.Context, reqs []*ProcessRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[*ProcessRequestWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *ProcessRequestWrapper) (*ProcessRequestWrapper, error) {
+		return c.ProcessRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // GetSystemInfo executes the GetSystemInfo one-way SOAP operation.
 func (c *Client) GetSystemInfo(ctx context.Context, req *SystemInfoWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -76,6 +89,16 @@ func (c *Client) GetSystemInfo(ctx context.Context, req *SystemInfoWrapper, opts
 	return nil
 }
 
+// GetSystemInfoBatch executes GetSystemInfo for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetSystemInfoBatch(ctx context.Context, reqs []*SystemInfoWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *SystemInfoWrapper) (struct{}, error) {
+		return struct{}{}, c.GetSystemInfo(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // UpdateUserData executes the UpdateUserData one-way SOAP operation.
 func (c *Client) UpdateUserData(ctx context.Context, req *UserDataWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -89,6 +112,16 @@ func (c *Client) UpdateUserData(ctx context.Context, req *UserDataWrapper, opts
 	return nil
 }
 
+// UpdateUserDataBatch executes UpdateUserData for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) UpdateUserDataBatch(ctx context.Context, reqs []*UserDataWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UserDataWrapper) (struct{}, error) {
+		return struct{}{}, c.UpdateUserData(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ValidateProcessRequest executes the ValidateProcessRequest one-way SOAP operation.
 func (c *Client) ValidateProcessRequest(ctx context.Context, req *ProcessRequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -101,3 +134,13 @@ func (c *Client) ValidateProcessRequest(ctx context.Context, req *ProcessRequest
 	}
 	return nil
 }
+
+// ValidateProcessRequestBatch executes ValidateProcessRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ValidateProcessRequestBatch(ctx context.Context, reqs []*ProcessRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *ProcessRequestWrapper) (struct{}, error) {
+		return struct{}{}, c.ValidateProcessRequest(ctx, req, callOpts...)
+	}, opts...)
+}
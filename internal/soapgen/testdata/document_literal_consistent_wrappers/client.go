@@ -2,7 +2,6 @@ package document_literal_consistent_wrappers
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,47 +34,68 @@ func (c *Client) Login(ctx context.Context, req *LoginWrapper, opts ...ClientOpt
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/document-literal-test/Login", reqEnvelope, opts...)
+	var result LoginResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/document-literal-test/Login", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result LoginResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// LoginBatch executes Login for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) LoginBatch(ctx context.Context, reqs []*LoginWrapper, opts ...soap.BatchOption) []soap.BatchResult[*LoginResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *LoginWrapper) (*LoginResponseWrapper, error) {
+		return c.Login(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // GetUser executes the GetUser SOAP operation.
 func (c *Client) GetUser(ctx context.Context, req *GetUserWrapper, opts ...ClientOption) (*GetUserResponseWrapper, error) {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/document-literal-test/GetUser", reqEnvelope, opts...)
+	var result GetUserResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/document-literal-test/GetUser", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result GetUserResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// GetUserBatch executes GetUser for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetUserBatch(ctx context.Context, reqs []*GetUserWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetUserResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetUserWrapper) (*GetUserResponseWrapper, error) {
+		return c.GetUser(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // Logout executes the Logout SOAP operation.
 func (c *Client) Logout(ctx context.Context, req *LogoutWrapper, opts ...ClientOption) (*LogoutResponseWrapper, error) {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/document-literal-test/Logout", reqEnvelope, opts...)
+	var result LogoutResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/document-literal-test/Logout", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result LogoutResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
+
+// LogoutBatch executes Logout for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) LogoutBatch(ctx context.Context, reqs []*LogoutWrapper, opts ...soap.BatchOption) []soap.BatchResult[*LogoutResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *LogoutWrapper) (*LogoutResponseWrapper, error) {
+		return c.Logout(ctx, req, callOpts...)
+	}, opts...)
+}
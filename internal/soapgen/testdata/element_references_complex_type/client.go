@@ -2,7 +2,6 @@ package element_references_complex_type
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,13 +34,20 @@ func (c *Client) GetItems(ctx context.Context, req *GetItemsWrapper, opts ...Cli
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "getItems", reqEnvelope, opts...)
+	var result GetItemsResponseWrapper
+	_, err = c.CallDecode(ctx, "getItems", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result GetItemsResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
+
+// GetItemsBatch executes GetItems for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetItemsBatch(ctx context.Context, reqs []*GetItemsWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetItemsResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetItemsWrapper) (*GetItemsResponseWrapper, error) {
+		return c.GetItems(ctx, req, callOpts...)
+	}, opts...)
+}
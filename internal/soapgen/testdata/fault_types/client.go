@@ -0,0 +1,275 @@
+package fault_types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/payments"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// Transfer executes the Transfer SOAP operation.
+func (c *Client) Transfer(ctx context.Context, req *TransferWrapper, opts ...ClientOption) (*TransferResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result TransferResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/payments/Transfer", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", decodeTransferFault(err))
+	}
+	return &result, nil
+}
+
+// TransferBatch executes Transfer for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TransferBatch(ctx context.Context, reqs []*TransferWrapper, opts ...soap.BatchOption) []soap.BatchResult[*TransferResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *TransferWrapper) (*TransferResponseWrapper, error) {
+		return c.Transfer(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// decodeTransferFault converts the SOAP faults declared by Transfer
+// into their error types. Other errors are returned unchanged.
+func decodeTransferFault(err error) error {
+	var soapErr *soap.Error
+	if !errors.As(err, &soapErr) || soapErr.Fault == nil || soapErr.Fault.Detail == nil {
+		return err
+	}
+	if detail := new(InvalidAccountWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return InvalidAccountFault{Detail: detail, Err: soapErr}
+	}
+	if detail := new(InsufficientFundsWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return InsufficientFundsFault{Detail: detail, Err: soapErr}
+	}
+	if detail := new(AccountLockedFaultWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return AccountLockedFault{Detail: detail, Err: soapErr}
+	}
+	if detail := new(LimitExceededWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return LimitExceededError{Detail: detail, Err: soapErr}
+	}
+	if detail := new(QuotaExceededWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return QuotaExceededFault{Detail: detail, Err: soapErr}
+	}
+	if detail := new(QuotaExceededFaultWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return QuotaExceededError{Detail: detail, Err: soapErr}
+	}
+	return err
+}
+
+// GetAccount executes the GetAccount SOAP operation.
+func (c *Client) GetAccount(ctx context.Context, req *GetAccountWrapper, opts ...ClientOption) (*GetAccountResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetAccountResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/payments/GetAccount", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", decodeGetAccountFault(err))
+	}
+	return &result, nil
+}
+
+// GetAccountBatch executes GetAccount for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetAccountBatch(ctx context.Context, reqs []*GetAccountWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetAccountResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetAccountWrapper) (*GetAccountResponseWrapper, error) {
+		return c.GetAccount(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// decodeGetAccountFault converts the SOAP faults declared by GetAccount
+// into their error types. Other errors are returned unchanged.
+func decodeGetAccountFault(err error) error {
+	var soapErr *soap.Error
+	if !errors.As(err, &soapErr) || soapErr.Fault == nil || soapErr.Fault.Detail == nil {
+		return err
+	}
+	if detail := new(InvalidAccountWrapper); soapErr.Fault.DecodeDetail(detail) == nil {
+		return InvalidAccountFault{Detail: detail, Err: soapErr}
+	}
+	return err
+}
+
+// InvalidAccountFault is returned when a call fails with a SOAP fault carrying
+// the InvalidAccount fault detail. Services return it to send that fault.
+type InvalidAccountFault struct {
+	// Detail is the decoded fault detail.
+	Detail *InvalidAccountWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e InvalidAccountFault) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with InvalidAccount detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e InvalidAccountFault) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// InsufficientFundsFault is returned when a call fails with a SOAP fault carrying
+// the InsufficientFunds fault detail. Services return it to send that fault.
+type InsufficientFundsFault struct {
+	// Detail is the decoded fault detail.
+	Detail *InsufficientFundsWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e InsufficientFundsFault) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with InsufficientFunds detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e InsufficientFundsFault) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// AccountLockedFault is returned when a call fails with a SOAP fault carrying
+// the AccountLockedFault fault detail. Services return it to send that fault.
+type AccountLockedFault struct {
+	// Detail is the decoded fault detail.
+	Detail *AccountLockedFaultWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e AccountLockedFault) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with AccountLockedFault detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e AccountLockedFault) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// LimitExceededError is returned when a call fails with a SOAP fault carrying
+// the LimitExceeded fault detail. Services return it to send that fault.
+type LimitExceededError struct {
+	// Detail is the decoded fault detail.
+	Detail *LimitExceededWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e LimitExceededError) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with LimitExceeded detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e LimitExceededError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// QuotaExceededFault is returned when a call fails with a SOAP fault carrying
+// the QuotaExceeded fault detail. Services return it to send that fault.
+type QuotaExceededFault struct {
+	// Detail is the decoded fault detail.
+	Detail *QuotaExceededWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e QuotaExceededFault) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with QuotaExceeded detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e QuotaExceededFault) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// QuotaExceededError is returned when a call fails with a SOAP fault carrying
+// the QuotaExceededFault fault detail. Services return it to send that fault.
+type QuotaExceededError struct {
+	// Detail is the decoded fault detail.
+	Detail *QuotaExceededFaultWrapper
+
+	// Err is the underlying SOAP error.
+	Err *soap.Error
+}
+
+// Error implements the error interface.
+func (e QuotaExceededError) Error() string {
+	if e.Err == nil {
+		return "SOAP fault with QuotaExceededFault detail"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying SOAP error, if any.
+func (e QuotaExceededError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
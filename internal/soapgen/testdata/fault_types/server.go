@@ -0,0 +1,81 @@
+package fault_types
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+	"net/http"
+)
+
+// Service is the interface implemented by servers of the SOAP service.
+//
+// Methods return one of the fault errors, such as InvalidAccountFault, to send a
+// declared fault with its detail, or a *soap.Fault to send a specific SOAP
+// fault; other errors are sent as server faults.
+type Service interface {
+	// Transfer handles the Transfer SOAP operation.
+	Transfer(ctx context.Context, req *TransferWrapper) (*TransferResponseWrapper, error)
+
+	// GetAccount handles the GetAccount SOAP operation.
+	GetAccount(ctx context.Context, req *GetAccountWrapper) (*GetAccountResponseWrapper, error)
+}
+
+// RegisterService registers the operations of the service on a SOAP server.
+// Requests are dispatched by SOAPAction, or by their request element when
+// the action is missing.
+func RegisterService(server *soap.Server, service Service) {
+	soap.Handle(server, "http://example.com/payments/Transfer", func(ctx context.Context, req *TransferWrapper) (*TransferResponseWrapper, error) {
+		resp, err := service.Transfer(ctx, req)
+		return resp, encodeFault(err)
+	})
+	soap.Handle(server, "http://example.com/payments/GetAccount", func(ctx context.Context, req *GetAccountWrapper) (*GetAccountResponseWrapper, error) {
+		resp, err := service.GetAccount(ctx, req)
+		return resp, encodeFault(err)
+	})
+}
+
+// NewHandler returns an HTTP handler serving the service.
+func NewHandler(service Service, opts ...soap.ServerOption) http.Handler {
+	server := soap.NewServer(opts...)
+	RegisterService(server, service)
+	return server
+}
+
+// encodeFault converts the fault errors returned by the service into server
+// SOAP faults carrying their detail. Other errors are returned unchanged.
+func encodeFault(err error) error {
+	if fault := (InvalidAccountFault{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	if fault := (InsufficientFundsFault{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	if fault := (AccountLockedFault{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	if fault := (LimitExceededError{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	if fault := (QuotaExceededFault{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	if fault := (QuotaExceededError{}); errors.As(err, &fault) && fault.Detail != nil {
+		return newDetailFault(err, fault.Detail)
+	}
+	return err
+}
+
+// newDetailFault returns a server SOAP fault carrying a fault detail element.
+func newDetailFault(err error, detail any) error {
+	content, marshalErr := xml.Marshal(detail)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode fault detail: %w", marshalErr)
+	}
+	return &soap.Fault{
+		FaultCode:   "Server",
+		FaultString: err.Error(),
+		Detail:      &soap.Detail{Content: content},
+	}
+}
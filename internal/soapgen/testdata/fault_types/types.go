@@ -0,0 +1,77 @@
+package fault_types
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Complex types
+
+// LimitExceededFault represents the LimitExceededFault complex type
+type LimitExceededFault struct {
+	Limit string `xml:"Limit"`
+}
+
+// TransferWrapper represents the Transfer element
+type TransferWrapper struct {
+	XMLName     xml.Name `xml:"http://example.com/payments Transfer"`
+	FromAccount string   `xml:"FromAccount"`
+	ToAccount   string   `xml:"ToAccount"`
+	Amount      string   `xml:"Amount"`
+}
+
+// TransferResponseWrapper represents the TransferResponse element
+type TransferResponseWrapper struct {
+	XMLName       xml.Name `xml:"http://example.com/payments TransferResponse"`
+	TransactionId string   `xml:"TransactionId"`
+}
+
+// GetAccountWrapper represents the GetAccount element
+type GetAccountWrapper struct {
+	XMLName   xml.Name `xml:"http://example.com/payments GetAccount"`
+	AccountId string   `xml:"AccountId"`
+}
+
+// GetAccountResponseWrapper represents the GetAccountResponse element
+type GetAccountResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/payments GetAccountResponse"`
+	Owner   string   `xml:"Owner"`
+}
+
+// InvalidAccountWrapper represents the InvalidAccount element
+type InvalidAccountWrapper struct {
+	XMLName   xml.Name `xml:"http://example.com/payments InvalidAccount"`
+	AccountId string   `xml:"AccountId"`
+	Reason    string   `xml:"Reason"`
+}
+
+// InsufficientFundsWrapper represents the InsufficientFunds element
+type InsufficientFundsWrapper struct {
+	XMLName   xml.Name `xml:"http://example.com/payments InsufficientFunds"`
+	Balance   string   `xml:"Balance"`
+	Requested string   `xml:"Requested"`
+}
+
+// AccountLockedFaultWrapper represents the AccountLockedFault element
+type AccountLockedFaultWrapper struct {
+	XMLName     xml.Name  `xml:"http://example.com/payments AccountLockedFault"`
+	LockedUntil time.Time `xml:"LockedUntil"`
+}
+
+// LimitExceededWrapper represents the LimitExceeded element
+type LimitExceededWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/payments LimitExceeded"`
+	Limit   string   `xml:"Limit"`
+}
+
+// QuotaExceededWrapper represents the QuotaExceeded element
+type QuotaExceededWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/payments QuotaExceeded"`
+	Quota   int32    `xml:"Quota"`
+}
+
+// QuotaExceededFaultWrapper represents the QuotaExceededFault element
+type QuotaExceededFaultWrapper struct {
+	XMLName    xml.Name `xml:"http://example.com/payments QuotaExceededFault"`
+	RetryAfter int32    `xml:"RetryAfter"`
+}
@@ -0,0 +1,102 @@
+package idempotent_operations
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/orders"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// GetOrder executes the GetOrder SOAP operation.
+func (c *Client) GetOrder(ctx context.Context, req *GetOrderWrapper, opts ...ClientOption) (*GetOrderResponseWrapper, error) {
+	opts = append([]ClientOption{soap.WithIdempotent(true)}, opts...)
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetOrderResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/orders/GetOrder", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetOrderBatch executes GetOrder for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetOrderBatch(ctx context.Context, reqs []*GetOrderWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetOrderResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetOrderWrapper) (*GetOrderResponseWrapper, error) {
+		return c.GetOrder(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// PlaceOrder executes the PlaceOrder SOAP operation.
+func (c *Client) PlaceOrder(ctx context.Context, req *PlaceOrderWrapper, opts ...ClientOption) (*PlaceOrderResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result PlaceOrderResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/orders/PlaceOrder", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// PlaceOrderBatch executes PlaceOrder for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) PlaceOrderBatch(ctx context.Context, reqs []*PlaceOrderWrapper, opts ...soap.BatchOption) []soap.BatchResult[*PlaceOrderResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *PlaceOrderWrapper) (*PlaceOrderResponseWrapper, error) {
+		return c.PlaceOrder(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// CancelOrder executes the CancelOrder one-way SOAP operation.
+func (c *Client) CancelOrder(ctx context.Context, req *CancelOrderWrapper, opts ...ClientOption) error {
+	opts = append([]ClientOption{soap.WithIdempotent(true)}, opts...)
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	_, err = c.Call(ctx, "http://example.com/orders/CancelOrder", reqEnvelope, opts...)
+	if err != nil {
+		return fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return nil
+}
+
+// CancelOrderBatch executes CancelOrder for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) CancelOrderBatch(ctx context.Context, reqs []*CancelOrderWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *CancelOrderWrapper) (struct{}, error) {
+		return struct{}{}, c.CancelOrder(ctx, req, callOpts...)
+	}, opts...)
+}
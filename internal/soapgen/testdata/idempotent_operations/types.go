@@ -0,0 +1,36 @@
+package idempotent_operations
+
+import (
+	"encoding/xml"
+)
+
+// GetOrderWrapper represents the GetOrder element
+type GetOrderWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/orders GetOrder"`
+	OrderId string   `xml:"OrderId"`
+}
+
+// GetOrderResponseWrapper represents the GetOrderResponse element
+type GetOrderResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/orders GetOrderResponse"`
+	Status  string   `xml:"Status"`
+}
+
+// PlaceOrderWrapper represents the PlaceOrder element
+type PlaceOrderWrapper struct {
+	XMLName  xml.Name `xml:"http://example.com/orders PlaceOrder"`
+	Sku      string   `xml:"Sku"`
+	Quantity int32    `xml:"Quantity"`
+}
+
+// PlaceOrderResponseWrapper represents the PlaceOrderResponse element
+type PlaceOrderResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/orders PlaceOrderResponse"`
+	OrderId string   `xml:"OrderId"`
+}
+
+// CancelOrderWrapper represents the CancelOrder element
+type CancelOrderWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/orders CancelOrder"`
+	OrderId string   `xml:"OrderId"`
+}
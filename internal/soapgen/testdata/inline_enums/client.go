@@ -2,7 +2,6 @@ package inline_enums
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,13 +34,20 @@ func (c *Client) GetServerProperties(ctx context.Context, req *GetServerProperti
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/inlineenums/GetServerProperties", reqEnvelope, opts...)
+	var result GetServerPropertiesResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/inlineenums/GetServerProperties", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result GetServerPropertiesResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
+
+// GetServerPropertiesBatch executes GetServerProperties for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetServerPropertiesBatch(ctx context.Context, reqs []*GetServerPropertiesRequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetServerPropertiesResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetServerPropertiesRequestWrapper) (*GetServerPropertiesResponseWrapper, error) {
+		return c.GetServerProperties(ctx, req, callOpts...)
+	}, opts...)
+}
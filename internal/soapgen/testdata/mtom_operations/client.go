@@ -0,0 +1,78 @@
+package mtom_operations
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/documents"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// UploadDocument executes the UploadDocument SOAP operation.
+func (c *Client) UploadDocument(ctx context.Context, req *UploadDocumentWrapper, opts ...ClientOption) (*UploadDocumentResponseWrapper, error) {
+	opts = append([]ClientOption{soap.WithMTOM(true), soap.WithXOPElements("UploadDocument/Content", "UploadDocument/Signature/Value")}, opts...)
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result UploadDocumentResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/documents/UploadDocument", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// UploadDocumentBatch executes UploadDocument for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) UploadDocumentBatch(ctx context.Context, reqs []*UploadDocumentWrapper, opts ...soap.BatchOption) []soap.BatchResult[*UploadDocumentResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *UploadDocumentWrapper) (*UploadDocumentResponseWrapper, error) {
+		return c.UploadDocument(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// DownloadDocument executes the DownloadDocument SOAP operation.
+func (c *Client) DownloadDocument(ctx context.Context, req *DownloadDocumentWrapper, opts ...ClientOption) (*DownloadDocumentResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result DownloadDocumentResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/documents/DownloadDocument", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// DownloadDocumentBatch executes DownloadDocument for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) DownloadDocumentBatch(ctx context.Context, reqs []*DownloadDocumentWrapper, opts ...soap.BatchOption) []soap.BatchResult[*DownloadDocumentResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DownloadDocumentWrapper) (*DownloadDocumentResponseWrapper, error) {
+		return c.DownloadDocument(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -0,0 +1,41 @@
+package mtom_operations
+
+import (
+	"encoding/xml"
+	soap "github.com/way-platform/soap-go"
+)
+
+// Complex types
+
+// SignatureType represents the SignatureType complex type
+type SignatureType struct {
+	Algorithm string            `xml:"Algorithm"`
+	Value     soap.Base64Binary `xml:"Value"`
+}
+
+// UploadDocumentWrapper represents the UploadDocument element
+type UploadDocumentWrapper struct {
+	XMLName   xml.Name          `xml:"http://example.com/documents UploadDocument"`
+	FileName  string            `xml:"FileName"`
+	Content   soap.Base64Binary `xml:"Content"`
+	Signature *SignatureType    `xml:"Signature,omitempty"`
+}
+
+// UploadDocumentResponseWrapper represents the UploadDocumentResponse element
+type UploadDocumentResponseWrapper struct {
+	XMLName    xml.Name `xml:"http://example.com/documents UploadDocumentResponse"`
+	DocumentId string   `xml:"DocumentId"`
+}
+
+// DownloadDocumentWrapper represents the DownloadDocument element
+type DownloadDocumentWrapper struct {
+	XMLName    xml.Name `xml:"http://example.com/documents DownloadDocument"`
+	DocumentId string   `xml:"DocumentId"`
+}
+
+// DownloadDocumentResponseWrapper represents the DownloadDocumentResponse element
+type DownloadDocumentResponseWrapper struct {
+	XMLName   xml.Name           `xml:"http://example.com/documents DownloadDocumentResponse"`
+	Content   soap.Base64Binary  `xml:"Content"`
+	Thumbnail *soap.Base64Binary `xml:"Thumbnail,omitempty"`
+}
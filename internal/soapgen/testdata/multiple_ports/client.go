@@ -0,0 +1,80 @@
+package multiple_ports
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoints(
+			"https://primary.example.com/tracking",
+			"https://dr.example.com/tracking",
+		),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// TrackShipment executes the TrackShipment SOAP operation.
+func (c *Client) TrackShipment(ctx context.Context, req *TrackShipmentWrapper, opts ...ClientOption) (*TrackShipmentResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result TrackShipmentResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/tracking/TrackShipment", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// TrackShipmentBatch executes TrackShipment for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TrackShipmentBatch(ctx context.Context, reqs []*TrackShipmentWrapper, opts ...soap.BatchOption) []soap.BatchResult[*TrackShipmentResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *TrackShipmentWrapper) (*TrackShipmentResponseWrapper, error) {
+		return c.TrackShipment(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// GetAuditLog executes the GetAuditLog SOAP operation.
+func (c *Client) GetAuditLog(ctx context.Context, req *GetAuditLogWrapper, opts ...ClientOption) (*GetAuditLogResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetAuditLogResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/tracking/GetAuditLog", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetAuditLogBatch executes GetAuditLog for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetAuditLogBatch(ctx context.Context, reqs []*GetAuditLogWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetAuditLogResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetAuditLogWrapper) (*GetAuditLogResponseWrapper, error) {
+		return c.GetAuditLog(ctx, req, callOpts...)
+	}, opts...)
+}
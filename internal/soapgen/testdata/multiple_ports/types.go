@@ -0,0 +1,29 @@
+package multiple_ports
+
+import (
+	"encoding/xml"
+)
+
+// TrackShipmentWrapper represents the TrackShipment element
+type TrackShipmentWrapper struct {
+	XMLName        xml.Name `xml:"http://example.com/tracking TrackShipment"`
+	TrackingNumber string   `xml:"TrackingNumber"`
+}
+
+// TrackShipmentResponseWrapper represents the TrackShipmentResponse element
+type TrackShipmentResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/tracking TrackShipmentResponse"`
+	Status  string   `xml:"Status"`
+}
+
+// GetAuditLogWrapper represents the GetAuditLog element
+type GetAuditLogWrapper struct {
+	XMLName        xml.Name `xml:"http://example.com/tracking GetAuditLog"`
+	TrackingNumber string   `xml:"TrackingNumber"`
+}
+
+// GetAuditLogResponseWrapper represents the GetAuditLogResponse element
+type GetAuditLogResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/tracking GetAuditLogResponse"`
+	Entry   []string `xml:"Entry"`
+}
@@ -41,6 +41,16 @@ func (c *Client) ProcessRequest(ctx context.Context, req *RequestWrapper, opts .
 	return nil
 }
 
+// ProcessRequestBatch executes ProcessRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessRequestBatch(ctx context.Context, reqs []*RequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *RequestWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessRequestLower executes the ProcessRequestLower one-way SOAP operation.
 func (c *Client) ProcessRequestLower(ctx context.Context, req *RequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -54,6 +64,16 @@ func (c *Client) ProcessRequestLower(ctx context.Context, req *RequestWrapper, o
 	return nil
 }
 
+// ProcessRequestLowerBatch executes ProcessRequestLower for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessRequestLowerBatch(ctx context.Context, reqs []*RequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *RequestWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessRequestLower(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessRequestUpper executes the ProcessRequestUpper one-way SOAP operation.
 func (c *Client) ProcessRequestUpper(ctx context.Context, req *REQUESTWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -67,6 +87,16 @@ func (c *Client) ProcessRequestUpper(ctx context.Context, req *REQUESTWrapper, o
 	return nil
 }
 
+// ProcessRequestUpperBatch executes ProcessRequestUpper for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessRequestUpperBatch(ctx context.Context, reqs []*REQUESTWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *REQUESTWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessRequestUpper(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessData executes the ProcessData one-way SOAP operation.
 func (c *Client) ProcessData(ctx context.Context, req *DataWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -80,6 +110,16 @@ func (c *Client) ProcessData(ctx context.Context, req *DataWrapper, opts ...Clie
 	return nil
 }
 
+// ProcessDataBatch executes ProcessData for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessDataBatch(ctx context.Context, reqs []*DataWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DataWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessData(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessDataLower executes the ProcessDataLower one-way SOAP operation.
 func (c *Client) ProcessDataLower(ctx context.Context, req *DataWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -93,6 +133,16 @@ func (c *Client) ProcessDataLower(ctx context.Context, req *DataWrapper, opts ..
 	return nil
 }
 
+// ProcessDataLowerBatch executes ProcessDataLower for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessDataLowerBatch(ctx context.Context, reqs []*DataWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DataWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessDataLower(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessDataUpper executes the ProcessDataUpper one-way SOAP operation.
 func (c *Client) ProcessDataUpper(ctx context.Context, req *DATAWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -106,6 +156,16 @@ func (c *Client) ProcessDataUpper(ctx context.Context, req *DATAWrapper, opts ..
 	return nil
 }
 
+// ProcessDataUpperBatch executes ProcessDataUpper for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessDataUpperBatch(ctx context.Context, reqs []*DATAWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *DATAWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessDataUpper(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ProcessExtremeCase executes the ProcessExtremeCase one-way SOAP operation.
 func (c *Client) ProcessExtremeCase(ctx context.Context, req *ExtremeCaseElementWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -119,6 +179,16 @@ func (c *Client) ProcessExtremeCase(ctx context.Context, req *ExtremeCaseElement
 	return nil
 }
 
+// ProcessExtremeCaseBatch executes ProcessExtremeCase for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ProcessExtremeCaseBatch(ctx context.Context, reqs []*ExtremeCaseElementWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *ExtremeCaseElementWrapper) (struct{}, error) {
+		return struct{}{}, c.ProcessExtremeCase(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // HandleRequest executes the HandleRequest one-way SOAP operation.
 func (c *Client) HandleRequest(ctx context.Context, req *RequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -132,6 +202,16 @@ func (c *Client) HandleRequest(ctx context.Context, req *RequestWrapper, opts ..
 	return nil
 }
 
+// HandleRequestBatch executes HandleRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) HandleRequestBatch(ctx context.Context, reqs []*RequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *RequestWrapper) (struct{}, error) {
+		return struct{}{}, c.HandleRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // ValidateRequest executes the ValidateRequest one-way SOAP operation.
 func (c *Client) ValidateRequest(ctx context.Context, req *RequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -145,6 +225,16 @@ func (c *Client) ValidateRequest(ctx context.Context, req *RequestWrapper, opts
 	return nil
 }
 
+// ValidateRequestBatch executes ValidateRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) ValidateRequestBatch(ctx context.Context, reqs []*RequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *RequestWrapper) (struct{}, error) {
+		return struct{}{}, c.ValidateRequest(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // SubmitRequest executes the SubmitRequest one-way SOAP operation.
 func (c *Client) SubmitRequest(ctx context.Context, req *RequestWrapper, opts ...ClientOption) error {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
@@ -157,3 +247,13 @@ func (c *Client) SubmitRequest(ctx context.Context, req *RequestWrapper, opts ..
 	}
 	return nil
 }
+
+// SubmitRequestBatch executes SubmitRequest for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) SubmitRequestBatch(ctx context.Context, reqs []*RequestWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *RequestWrapper) (struct{}, error) {
+		return struct{}{}, c.SubmitRequest(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -0,0 +1,78 @@
+package rate_limits
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/inventory"),
+		soap.WithActionRateLimit("http://example.com/inventory/GetStock", 2.5, 5),
+		soap.WithActionRateLimit("http://example.com/inventory/StockChanged", 10, 1),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// GetStock returns the quantity in stock for a SKU.
+func (c *Client) GetStock(ctx context.Context, req *GetStockWrapper, opts ...ClientOption) (*GetStockResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetStockResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/inventory/GetStock", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetStockBatch executes GetStock for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetStockBatch(ctx context.Context, reqs []*GetStockWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetStockResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetStockWrapper) (*GetStockResponseWrapper, error) {
+		return c.GetStock(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// StockChanged executes the StockChanged one-way SOAP operation.
+func (c *Client) StockChanged(ctx context.Context, req *StockChangedWrapper, opts ...ClientOption) error {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	_, err = c.Call(ctx, "http://example.com/inventory/StockChanged", reqEnvelope, opts...)
+	if err != nil {
+		return fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return nil
+}
+
+// StockChangedBatch executes StockChanged for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) StockChangedBatch(ctx context.Context, reqs []*StockChangedWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *StockChangedWrapper) (struct{}, error) {
+		return struct{}{}, c.StockChanged(ctx, req, callOpts...)
+	}, opts...)
+}
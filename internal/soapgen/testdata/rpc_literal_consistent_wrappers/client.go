@@ -2,7 +2,6 @@ package rpc_literal_consistent_wrappers
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	soap "github.com/way-platform/soap-go"
 )
@@ -35,30 +34,44 @@ func (c *Client) Authenticate(ctx context.Context, req *AuthenticateWrapper, opt
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/rpc-literal-test/Authenticate", reqEnvelope, opts...)
+	var result AuthenticateResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/rpc-literal-test/Authenticate", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result AuthenticateResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
 
+// AuthenticateBatch executes Authenticate for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) AuthenticateBatch(ctx context.Context, reqs []*AuthenticateWrapper, opts ...soap.BatchOption) []soap.BatchResult[*AuthenticateResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *AuthenticateWrapper) (*AuthenticateResponseWrapper, error) {
+		return c.Authenticate(ctx, req, callOpts...)
+	}, opts...)
+}
+
 // FetchData executes the FetchData SOAP operation.
 func (c *Client) FetchData(ctx context.Context, req *FetchDataWrapper, opts ...ClientOption) (*FetchDataResponseWrapper, error) {
 	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
 	}
-	respEnvelope, err := c.Call(ctx, "http://example.com/rpc-literal-test/FetchData", reqEnvelope, opts...)
+	var result FetchDataResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/rpc-literal-test/FetchData", reqEnvelope, &result, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("SOAP call failed: %w", err)
 	}
-	var result FetchDataResponseWrapper
-	if err := xml.Unmarshal(respEnvelope.Body.Content, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
 	return &result, nil
 }
+
+// FetchDataBatch executes FetchData for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) FetchDataBatch(ctx context.Context, reqs []*FetchDataWrapper, opts ...soap.BatchOption) []soap.BatchResult[*FetchDataResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *FetchDataWrapper) (*FetchDataResponseWrapper, error) {
+		return c.FetchData(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -0,0 +1,36 @@
+package server_stubs
+
+import (
+	"context"
+	soap "github.com/way-platform/soap-go"
+	"net/http"
+)
+
+// Service is the interface implemented by servers of the SOAP service.
+//
+// Methods return a *soap.Fault to send a specific SOAP fault; other errors
+// are sent as server faults.
+type Service interface {
+	// GetStock returns the quantity in stock for a SKU.
+	GetStock(ctx context.Context, req *GetStockWrapper) (*GetStockResponseWrapper, error)
+
+	// StockChanged handles the StockChanged SOAP operation.
+	StockChanged(ctx context.Context, req *StockChangedWrapper) error
+}
+
+// RegisterService registers the operations of the service on a SOAP server.
+// Requests are dispatched by SOAPAction, or by their request element when
+// the action is missing.
+func RegisterService(server *soap.Server, service Service) {
+	soap.Handle(server, "http://example.com/inventory/GetStock", service.GetStock)
+	soap.Handle(server, "http://example.com/inventory/StockChanged", func(ctx context.Context, req *StockChangedWrapper) (*struct{}, error) {
+		return nil, service.StockChanged(ctx, req)
+	})
+}
+
+// NewHandler returns an HTTP handler serving the service.
+func NewHandler(service Service, opts ...soap.ServerOption) http.Handler {
+	server := soap.NewServer(opts...)
+	RegisterService(server, service)
+	return server
+}
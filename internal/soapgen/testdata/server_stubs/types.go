@@ -0,0 +1,24 @@
+package server_stubs
+
+import (
+	"encoding/xml"
+)
+
+// GetStockWrapper represents the GetStock element
+type GetStockWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/inventory GetStock"`
+	Sku     string   `xml:"Sku"`
+}
+
+// GetStockResponseWrapper represents the GetStockResponse element
+type GetStockResponseWrapper struct {
+	XMLName  xml.Name `xml:"http://example.com/inventory GetStockResponse"`
+	Quantity int32    `xml:"Quantity"`
+}
+
+// StockChangedWrapper represents the StockChanged element
+type StockChangedWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/inventory StockChanged"`
+	Sku     string   `xml:"Sku"`
+	Delta   int32    `xml:"Delta"`
+}
@@ -40,3 +40,13 @@ func (c *Client) TestOperation(ctx context.Context, req *StatesContainerWrapper,
 	}
 	return nil
 }
+
+// TestOperationBatch executes TestOperation for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) TestOperationBatch(ctx context.Context, reqs []*StatesContainerWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *StatesContainerWrapper) (struct{}, error) {
+		return struct{}{}, c.TestOperation(ctx, req, callOpts...)
+	}, opts...)
+}
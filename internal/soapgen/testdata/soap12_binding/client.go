@@ -0,0 +1,77 @@
+package soap12_binding
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/soap12/quotes"),
+		soap.WithSOAPVersion(soap.Version12),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// GetQuote executes the GetQuote SOAP operation.
+func (c *Client) GetQuote(ctx context.Context, req *GetQuoteWrapper, opts ...ClientOption) (*GetQuoteResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req), soap.WithVersion(soap.Version12))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetQuoteResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/soap12/GetQuote", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetQuoteBatch executes GetQuote for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetQuoteBatch(ctx context.Context, reqs []*GetQuoteWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetQuoteResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetQuoteWrapper) (*GetQuoteResponseWrapper, error) {
+		return c.GetQuote(ctx, req, callOpts...)
+	}, opts...)
+}
+
+// NotifyTrade executes the NotifyTrade one-way SOAP operation.
+func (c *Client) NotifyTrade(ctx context.Context, req *NotifyTradeWrapper, opts ...ClientOption) error {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req), soap.WithVersion(soap.Version12))
+	if err != nil {
+		return fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	_, err = c.Call(ctx, "http://example.com/soap12/NotifyTrade", reqEnvelope, opts...)
+	if err != nil {
+		return fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return nil
+}
+
+// NotifyTradeBatch executes NotifyTrade for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) NotifyTradeBatch(ctx context.Context, reqs []*NotifyTradeWrapper, opts ...soap.BatchOption) []soap.BatchResult[struct{}] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *NotifyTradeWrapper) (struct{}, error) {
+		return struct{}{}, c.NotifyTrade(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -0,0 +1,24 @@
+package soap12_binding
+
+import (
+	"encoding/xml"
+)
+
+// GetQuoteWrapper represents the GetQuote element
+type GetQuoteWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/soap12 GetQuote"`
+	Symbol  string   `xml:"Symbol"`
+}
+
+// GetQuoteResponseWrapper represents the GetQuoteResponse element
+type GetQuoteResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/soap12 GetQuoteResponse"`
+	Price   float64  `xml:"Price"`
+}
+
+// NotifyTradeWrapper represents the NotifyTrade element
+type NotifyTradeWrapper struct {
+	XMLName  xml.Name `xml:"http://example.com/soap12 NotifyTrade"`
+	Symbol   string   `xml:"Symbol"`
+	Quantity int32    `xml:"Quantity"`
+}
@@ -0,0 +1,57 @@
+package soap_headers
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/accounts"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// GetBalance executes the GetBalance SOAP operation.
+//
+// Request headers (send with soap.WithHeader): CredentialsWrapper, TracingWrapper.
+//
+// Response headers (read with soap.WithResponseHeader): QuotaWrapper.
+func (c *Client) GetBalance(ctx context.Context, req *GetBalanceWrapper, opts ...ClientOption) (*GetBalanceResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetBalanceResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/accounts/GetBalance", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetBalanceBatch executes GetBalance for each request, with the concurrency and
+// error handling of the batch options. Results are in the order of the requests.
+// Client options are applied to each call with soap.WithBatchCallOptions.
+func (c *Client) GetBalanceBatch(ctx context.Context, reqs []*GetBalanceWrapper, opts ...soap.BatchOption) []soap.BatchResult[*GetBalanceResponseWrapper] {
+	callOpts := soap.BatchCallOptions(opts...)
+	return soap.Batch(ctx, reqs, func(ctx context.Context, req *GetBalanceWrapper) (*GetBalanceResponseWrapper, error) {
+		return c.GetBalance(ctx, req, callOpts...)
+	}, opts...)
+}
@@ -0,0 +1,35 @@
+package soap_headers
+
+import (
+	"encoding/xml"
+)
+
+// CredentialsWrapper represents the Credentials element
+type CredentialsWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/accounts Credentials"`
+	ApiKey  string   `xml:"ApiKey"`
+}
+
+// TracingWrapper represents the Tracing element
+type TracingWrapper struct {
+	XMLName       xml.Name `xml:"http://example.com/accounts Tracing"`
+	CorrelationId string   `xml:"CorrelationId"`
+}
+
+// QuotaWrapper represents the Quota element
+type QuotaWrapper struct {
+	XMLName   xml.Name `xml:"http://example.com/accounts Quota"`
+	Remaining int32    `xml:"Remaining"`
+}
+
+// GetBalanceWrapper represents the GetBalance element
+type GetBalanceWrapper struct {
+	XMLName   xml.Name `xml:"http://example.com/accounts GetBalance"`
+	AccountId string   `xml:"AccountId"`
+}
+
+// GetBalanceResponseWrapper represents the GetBalanceResponse element
+type GetBalanceResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/accounts GetBalanceResponse"`
+	Balance string   `xml:"Balance"`
+}
@@ -0,0 +1,62 @@
+package swa_attachments
+
+import (
+	"context"
+	"fmt"
+	soap "github.com/way-platform/soap-go"
+)
+
+// ClientOption configures a Client.
+type ClientOption = soap.ClientOption
+
+// Client is a SOAP client for this service.
+type Client struct {
+	*soap.Client
+}
+
+// NewClient creates a new SOAP client.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	soapOpts := append([]soap.ClientOption{
+		soap.WithEndpoint("http://example.com/claims"),
+	}, opts...)
+	soapClient, err := soap.NewClient(soapOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP client: %w", err)
+	}
+	return &Client{
+		Client: soapClient,
+	}, nil
+}
+
+// SubmitClaim executes the SubmitClaim SOAP operation.
+//
+// Request attachments (multipart/related): photo (image/jpeg, image/png), report (application/pdf).
+func (c *Client) SubmitClaim(ctx context.Context, req *SubmitClaimWrapper, attachments []soap.Attachment, opts ...ClientOption) (*SubmitClaimResponseWrapper, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	reqEnvelope.Attachments = attachments
+	var result SubmitClaimResponseWrapper
+	_, err = c.CallDecode(ctx, "http://example.com/claims/SubmitClaim", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, nil
+}
+
+// GetClaimForm executes the GetClaimForm SOAP operation.
+//
+// Response attachments (multipart/related): form (application/pdf).
+func (c *Client) GetClaimForm(ctx context.Context, req *GetClaimFormWrapper, opts ...ClientOption) (*GetClaimFormResponseWrapper, []soap.Attachment, error) {
+	reqEnvelope, err := soap.NewEnvelope(soap.WithBody(req))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create SOAP envelope: %w", err)
+	}
+	var result GetClaimFormResponseWrapper
+	respEnvelope, err := c.CallDecode(ctx, "http://example.com/claims/GetClaimForm", reqEnvelope, &result, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("SOAP call failed: %w", err)
+	}
+	return &result, respEnvelope.Attachments, nil
+}
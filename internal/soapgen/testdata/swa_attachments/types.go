@@ -0,0 +1,30 @@
+package swa_attachments
+
+import (
+	"encoding/xml"
+)
+
+// SubmitClaimWrapper represents the SubmitClaim element
+type SubmitClaimWrapper struct {
+	XMLName     xml.Name `xml:"http://example.com/claims SubmitClaim"`
+	ClaimId     string   `xml:"ClaimId"`
+	Description string   `xml:"Description"`
+}
+
+// SubmitClaimResponseWrapper represents the SubmitClaimResponse element
+type SubmitClaimResponseWrapper struct {
+	XMLName  xml.Name `xml:"http://example.com/claims SubmitClaimResponse"`
+	Accepted bool     `xml:"Accepted"`
+}
+
+// GetClaimFormWrapper represents the GetClaimForm element
+type GetClaimFormWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/claims GetClaimForm"`
+	ClaimId string   `xml:"ClaimId"`
+}
+
+// GetClaimFormResponseWrapper represents the GetClaimFormResponse element
+type GetClaimFormResponseWrapper struct {
+	XMLName xml.Name `xml:"http://example.com/claims GetClaimFormResponse"`
+	Status  string   `xml:"Status"`
+}
@@ -78,6 +78,9 @@ func mapXSDTypeToGoWithContext(xsdType string, ctx *SchemaContext) string {
 
 	// Try standard XSD type parsing
 	parsedType := xsd.ParseType(xsdType)
+	if parsedType == xsd.Base64Binary && ctx.generator != nil && ctx.generator.useBase64BinaryType() {
+		return "soap.Base64Binary"
+	}
 	if !parsedType.IsCustomType() {
 		return mapXSDTypeToGo(parsedType)
 	}
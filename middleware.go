@@ -0,0 +1,52 @@
+package soap
+
+import (
+	"context"
+	"slices"
+)
+
+// Invoker executes a SOAP call of a request envelope and returns the response
+// envelope.
+type Invoker func(ctx context.Context, action string, req *Envelope) (*Envelope, error)
+
+// Middleware intercepts the SOAP calls of a client at the envelope level. It
+// may change the request, call next zero or more times, and inspect or
+// replace the response or error.
+//
+// Unlike [WithInterceptor], which wraps the HTTP transport, middleware works
+// on structured envelopes: it runs before the request is marshalled and after
+// the response is unmarshalled, once per call, outside of retries. SOAP faults
+// are returned as [*Error] with the response envelope.
+type Middleware func(ctx context.Context, action string, req *Envelope, next Invoker) (*Envelope, error)
+
+// WithMiddleware adds middleware to the calls of the Client. The first
+// middleware added is the outermost.
+//
+// Middleware receives a copy of the request envelope, which it may modify.
+// The headers of [WithHeader], [WithAddressing] and WS-Security are added to
+// the request after all middleware, so that security headers cover its
+// changes.
+//
+// With middleware, [Client.CallDecode] reads responses into memory like
+// [Client.Call], so that middleware can inspect the response body.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *clientConfig) {
+		c.middleware = append(slices.Clip(c.middleware), middleware...)
+	}
+}
+
+// invoke executes a call through the middleware of the configuration,
+// ending with the invoker.
+func (c clientConfig) invoke(ctx context.Context, action string, req *Envelope, invoker Invoker) (*Envelope, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	next := invoker
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		middleware, inner := c.middleware[i], next
+		next = func(ctx context.Context, action string, req *Envelope) (*Envelope, error) {
+			return middleware(ctx, action, req, inner)
+		}
+	}
+	return next(ctx, action, req.clone())
+}
@@ -0,0 +1,139 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type traceHeader struct {
+	XMLName xml.Name `xml:"http://example.com/trace Trace"`
+	ID      string   `xml:"ID"`
+}
+
+func TestClient_Middleware(t *testing.T) {
+	t.Parallel()
+	echo := newEchoHandler()
+	var requests atomic.Int32
+	var mu sync.Mutex
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		lastBody = body
+		mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		echo.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("order and structured envelopes", func(t *testing.T) {
+		var calls []string
+		record := func(name string) Middleware {
+			return func(ctx context.Context, action string, req *Envelope, next Invoker) (*Envelope, error) {
+				calls = append(calls, name+" request")
+				resp, err := next(ctx, action, req)
+				calls = append(calls, name+" response")
+				return resp, err
+			}
+		}
+		addTrace := func(ctx context.Context, action string, req *Envelope, next Invoker) (*Envelope, error) {
+			if err := req.AddHeader(&traceHeader{ID: "trace-1"}, false, ""); err != nil {
+				return nil, err
+			}
+			resp, err := next(ctx, action, req)
+			if err != nil {
+				return nil, err
+			}
+			var body echoResponse
+			if err := resp.DecodeBody(&body); err != nil {
+				return nil, err
+			}
+			body.Message += " (checked)"
+			return NewEnvelope(WithBody(&body))
+		}
+		client, err := NewClient(WithEndpoint(server.URL), WithMiddleware(record("outer"), addTrace))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		var resp echoResponse
+		_, err = client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp, WithMiddleware(record("call")))
+		if err != nil {
+			t.Fatalf("Client.CallDecode() error = %v", err)
+		}
+		if resp.Message != "hello (checked)" {
+			t.Errorf("Expected the message of the middleware, got: %q", resp.Message)
+		}
+		want := []string{"outer request", "call request", "call response", "outer response"}
+		if !slices.Equal(calls, want) {
+			t.Errorf("Expected calls %v, got: %v", want, calls)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !bytes.Contains(lastBody, []byte("trace-1")) {
+			t.Errorf("Expected the trace header in the request, got: %s", lastBody)
+		}
+		if reqEnv.Header != nil {
+			t.Errorf("Expected the caller's envelope to be unchanged, got header: %+v", reqEnv.Header)
+		}
+	})
+
+	t.Run("short circuit", func(t *testing.T) {
+		cached, _ := NewEnvelope(WithBody(&echoResponse{Message: "cached"}))
+		cache := func(ctx context.Context, action string, req *Envelope, next Invoker) (*Envelope, error) {
+			return cached, nil
+		}
+		client, err := NewClient(WithEndpoint(server.URL), WithMiddleware(cache))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		before := requests.Load()
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		resp, err := client.Call(context.Background(), "urn:echo", reqEnv)
+		if err != nil {
+			t.Fatalf("Client.Call() error = %v", err)
+		}
+		var body echoResponse
+		if err := resp.DecodeBody(&body); err != nil || body.Message != "cached" {
+			t.Errorf("Expected the cached response, got: %q, %v", body.Message, err)
+		}
+		if got := requests.Load(); got != before {
+			t.Errorf("Expected no request, got: %d", got-before)
+		}
+	})
+
+	t.Run("faults", func(t *testing.T) {
+		server, _ := newCountingServer(t, http.StatusServiceUnavailable)
+		var attempts int
+		var callErr error
+		observe := func(ctx context.Context, action string, req *Envelope, next Invoker) (*Envelope, error) {
+			attempts++
+			resp, err := next(ctx, action, req)
+			callErr = err
+			return resp, err
+		}
+		client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(2), WithIdempotent(true), WithMiddleware(observe))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv)
+		var soapErr *Error
+		if !errors.As(err, &soapErr) || !errors.As(callErr, &soapErr) || soapErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected the HTTP error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected the middleware to run once across retries, got: %d", attempts)
+		}
+	})
+}
@@ -0,0 +1,415 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// XOPNamespace is the XML-binary Optimized Packaging (XOP) namespace.
+const XOPNamespace = "http://www.w3.org/2004/08/xop/include"
+
+// mtomThreshold is the minimum decoded size of base64 element content
+// that is sent as a separate binary part when MTOM is enabled.
+const mtomThreshold = 1024
+
+// rootContentID is the Content-ID of the root part of outgoing multipart/related messages.
+const rootContentID = "<root.message@soap-go>"
+
+// Base64Binary is binary data encoded as xsd:base64Binary.
+//
+// Values are marshalled as base64 text. When a response is received as an
+// MTOM/XOP package, [Client] resolves xop:Include references back into
+// base64 text before decoding, so Base64Binary fields are populated the same
+// way regardless of whether the service optimized them.
+type Base64Binary []byte
+
+// MarshalText implements [encoding.TextMarshaler].
+func (b Base64Binary) MarshalText() ([]byte, error) {
+	result := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(result, b)
+	return result, nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// Whitespace, as used by line-wrapped base64 encoders, is ignored.
+func (b *Base64Binary) UnmarshalText(text []byte) error {
+	text = bytes.Join(bytes.Fields(text), nil)
+	result := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(result, text)
+	if err != nil {
+		return fmt.Errorf("invalid base64Binary content: %w", err)
+	}
+	*b = result[:n]
+	return nil
+}
+
+// Reader returns an [io.Reader] reading the binary data.
+func (b Base64Binary) Reader() io.Reader {
+	return bytes.NewReader(b)
+}
+
+// WithMTOM controls whether requests are sent as MTOM/XOP packages.
+// Defaults to false.
+//
+// When enabled, the base64 content of at least 1 KiB of the elements set with
+// [WithXOPElements] is moved out of the envelope into a binary MIME part and
+// replaced by an xop:Include reference. MTOM responses are always decoded,
+// regardless of this option.
+func WithMTOM(enabled bool) ClientOption {
+	return func(c *clientConfig) {
+		c.mtom = enabled
+	}
+}
+
+// WithXOPElements sets the elements of requests whose content is binary, such
+// as those of type xsd:base64Binary, and may be optimized with [WithMTOM].
+// Elements are identified by their path of local names from the body, such as
+// "UploadDocument/Content". Generated clients set the elements of their MTOM
+// operations from the schema.
+func WithXOPElements(paths ...string) ClientOption {
+	return func(c *clientConfig) {
+		c.xopElements = paths
+	}
+}
+
+// mimePart is a single part of a multipart/related message.
+type mimePart struct {
+	header textproto.MIMEHeader
+	data   []byte
+}
+
+// contentID returns the Content-ID of the part without angle brackets.
+func (p *mimePart) contentID() string {
+	return strings.Trim(p.header.Get("Content-ID"), "<>")
+}
+
+// encodeMultipartRelated packages a serialized envelope as a multipart/related message.
+// With mtom set, the binary elements of the envelope are XOP-optimized and it is
+// sent as an MTOM package; otherwise it is sent as the root part of a SOAP with
+// Attachments message.
+// The attachments follow as additional parts. It returns the message body and its Content-Type.
+func encodeMultipartRelated(
+	xmlData []byte,
+	version Version,
+	action string,
+	mtom bool,
+	xopElements []string,
+	attachments []Attachment,
+) ([]byte, string, error) {
+	root, parts := xmlData, []*mimePart(nil)
+	if mtom {
+		var err error
+		if root, parts, err = optimizeXOP(xmlData, xopElements); err != nil {
+			return nil, "", err
+		}
+	}
+	for i, attachment := range attachments {
+		parts = append(parts, attachmentPart(attachment, i))
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	rootType := contentType(version, action)
+	rootMediaType, _, _ := mime.ParseMediaType(rootType)
+	rootHeader := textproto.MIMEHeader{}
+	if mtom {
+		rootHeader.Set("Content-Type", mime.FormatMediaType("application/xop+xml", map[string]string{
+			"charset": "utf-8",
+			"type":    rootType,
+		}))
+	} else {
+		rootHeader.Set("Content-Type", rootType)
+	}
+	rootHeader.Set("Content-Transfer-Encoding", "8bit")
+	rootHeader.Set("Content-ID", rootContentID)
+	if err := writeMIMEPart(w, rootHeader, root); err != nil {
+		return nil, "", err
+	}
+	for _, part := range parts {
+		if err := writeMIMEPart(w, part.header, part.data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart message: %w", err)
+	}
+	params := map[string]string{
+		"type":     rootMediaType,
+		"start":    rootContentID,
+		"boundary": w.Boundary(),
+	}
+	if mtom {
+		params["type"] = "application/xop+xml"
+		params["start-info"] = rootType
+	}
+	return body.Bytes(), mime.FormatMediaType("multipart/related", params), nil
+}
+
+// writeMIMEPart writes a single part to a multipart message.
+func writeMIMEPart(w *multipart.Writer, header textproto.MIMEHeader, data []byte) error {
+	pw, err := w.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create MIME part: %w", err)
+	}
+	if _, err := pw.Write(data); err != nil {
+		return fmt.Errorf("failed to write MIME part: %w", err)
+	}
+	return nil
+}
+
+// optimizeXOP replaces the base64 content above the MTOM threshold of the
+// binary elements of an envelope, by their paths from the body, with
+// xop:Include references, returning the rewritten XML and the extracted binary
+// parts.
+func optimizeXOP(xmlData []byte, paths []string) ([]byte, []*mimePart, error) {
+	if len(paths) == 0 {
+		return xmlData, nil, nil
+	}
+	type element struct {
+		name     string
+		start    int64
+		hasChild bool
+	}
+	type replacement struct {
+		start, end int64
+		part       *mimePart
+	}
+	var stack []element
+	var replacements []replacement
+	d := xml.NewDecoder(bytes.NewReader(xmlData))
+	for {
+		offset := d.InputOffset()
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse envelope for MTOM: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(stack) > 0 {
+				stack[len(stack)-1].hasChild = true
+			}
+			stack = append(stack, element{name: t.Name.Local, start: d.InputOffset()})
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			current := stack[len(stack)-1]
+			// Paths start below Envelope/Body
+			var path []string
+			if len(stack) > 2 && stack[1].name == "Body" {
+				for _, e := range stack[2:] {
+					path = append(path, e.name)
+				}
+			}
+			stack = stack[:len(stack)-1]
+			if current.hasChild || offset <= current.start || !slices.Contains(paths, strings.Join(path, "/")) {
+				continue
+			}
+			data, ok := decodeCanonicalBase64(xmlData[current.start:offset])
+			if !ok || len(data) < mtomThreshold {
+				continue
+			}
+			part := &mimePart{header: textproto.MIMEHeader{}, data: data}
+			part.header.Set("Content-Type", "application/octet-stream")
+			part.header.Set("Content-Transfer-Encoding", "binary")
+			part.header.Set("Content-ID", "<"+strconv.Itoa(len(replacements)+1)+".part@soap-go>")
+			replacements = append(replacements, replacement{start: current.start, end: offset, part: part})
+		}
+	}
+	if len(replacements) == 0 {
+		return xmlData, nil, nil
+	}
+	var result bytes.Buffer
+	parts := make([]*mimePart, 0, len(replacements))
+	var last int64
+	for _, r := range replacements {
+		result.Write(xmlData[last:r.start])
+		fmt.Fprintf(&result, `<xop:Include xmlns:xop="%s" href="cid:%s"/>`, XOPNamespace, url.PathEscape(r.part.contentID()))
+		last = r.end
+		parts = append(parts, r.part)
+	}
+	result.Write(xmlData[last:])
+	return result.Bytes(), parts, nil
+}
+
+// decodeCanonicalBase64 decodes text that is canonical base64, i.e. text that
+// re-encodes to exactly the same characters. This guarantees that the receiver
+// reconstructs an identical XML infoset when resolving the XOP reference.
+func decodeCanonicalBase64(text []byte) ([]byte, bool) {
+	if len(text) == 0 || len(text)%4 != 0 {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return nil, false
+	}
+	if base64.StdEncoding.EncodeToString(data) != string(text) {
+		return nil, false
+	}
+	return data, true
+}
+
+// isMultipartRelated reports whether the Content-Type denotes a multipart/related message.
+func isMultipartRelated(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "multipart/related"
+}
+
+// decodeMultipartRelated splits a multipart/related message into its root
+// part and the remaining parts. The root part is identified by the start
+// parameter, falling back to the first part.
+func decodeMultipartRelated(contentType string, body []byte) (*mimePart, []*mimePart, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid multipart content type: %w", err)
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, nil, fmt.Errorf("multipart content type has no boundary")
+	}
+	start := strings.Trim(params["start"], "<>")
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	var root *mimePart
+	var parts []*mimePart
+	for {
+		p, err := r.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read MIME part: %w", err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read MIME part: %w", err)
+		}
+		part := &mimePart{header: p.Header, data: data}
+		if strings.EqualFold(strings.TrimSpace(part.header.Get("Content-Transfer-Encoding")), "base64") {
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil)))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decode base64 MIME part: %w", err)
+			}
+			part.data = decoded
+		}
+		if root == nil && (start == "" || part.contentID() == start) {
+			root = part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if root == nil {
+		return nil, nil, fmt.Errorf("multipart message has no root part")
+	}
+	return root, parts, nil
+}
+
+// decodeResponseBody returns the SOAP envelope XML of a response body and its attachments.
+// Multipart/related messages are unpacked and their xop:Include references resolved;
+// parts that are not referenced from the envelope are returned as attachments.
+func decodeResponseBody(contentType string, body []byte) ([]byte, []Attachment, error) {
+	if !isMultipartRelated(contentType) {
+		return body, nil, nil
+	}
+	root, parts, err := decodeMultipartRelated(contentType, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope, remaining, err := resolveXOP(root.data, parts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var attachments []Attachment
+	for _, part := range remaining {
+		attachments = append(attachments, partAttachment(part))
+	}
+	return envelope, attachments, nil
+}
+
+// resolveXOP replaces xop:Include references in the root XML with the base64
+// encoded content of the referenced parts. Parts that were referenced are
+// removed from the returned slice.
+func resolveXOP(root []byte, parts []*mimePart) ([]byte, []*mimePart, error) {
+	type replacement struct {
+		start, end int64
+		part       *mimePart
+	}
+	var replacements []replacement
+	d := xml.NewDecoder(bytes.NewReader(root))
+	for {
+		offset := d.InputOffset()
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse XOP root part: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != XOPNamespace || start.Name.Local != "Include" {
+			continue
+		}
+		var href string
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "href" {
+				href = attr.Value
+			}
+		}
+		if err := d.Skip(); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse XOP include: %w", err)
+		}
+		part, err := findXOPPart(href, parts)
+		if err != nil {
+			return nil, nil, err
+		}
+		replacements = append(replacements, replacement{start: offset, end: d.InputOffset(), part: part})
+	}
+	if len(replacements) == 0 {
+		return root, parts, nil
+	}
+	var result bytes.Buffer
+	referenced := make(map[*mimePart]bool, len(replacements))
+	var last int64
+	for _, r := range replacements {
+		result.Write(root[last:r.start])
+		result.WriteString(base64.StdEncoding.EncodeToString(r.part.data))
+		last = r.end
+		referenced[r.part] = true
+	}
+	result.Write(root[last:])
+	var remaining []*mimePart
+	for _, part := range parts {
+		if !referenced[part] {
+			remaining = append(remaining, part)
+		}
+	}
+	return result.Bytes(), remaining, nil
+}
+
+// findXOPPart finds the part referenced by a cid: URL.
+func findXOPPart(href string, parts []*mimePart) (*mimePart, error) {
+	if !strings.HasPrefix(href, "cid:") {
+		return nil, fmt.Errorf("unsupported XOP include reference: %q", href)
+	}
+	cid, err := url.PathUnescape(normalizeContentID(href))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XOP include reference %q: %w", href, err)
+	}
+	for _, part := range parts {
+		if part.contentID() == cid {
+			return part, nil
+		}
+	}
+	return nil, fmt.Errorf("XOP include references missing MIME part %q", cid)
+}
@@ -0,0 +1,180 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type documentPayload struct {
+	XMLName xml.Name     `xml:"http://example.com/documents Document"`
+	Name    string       `xml:"Name"`
+	Content Base64Binary `xml:"Content"`
+}
+
+func testDocument() documentPayload {
+	content := bytes.Repeat([]byte("%PDF-1.7 binary\x00\x01\x02"), 200)
+	return documentPayload{Name: "report.pdf", Content: content}
+}
+
+func TestBase64Binary(t *testing.T) {
+	t.Parallel()
+	doc := documentPayload{Name: "hello.txt", Content: Base64Binary("hello world")}
+	data, err := xml.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), "<Content>aGVsbG8gd29ybGQ=</Content>") {
+		t.Errorf("Expected base64 content, got: %s", data)
+	}
+	var decoded documentPayload
+	wrapped := `<Document xmlns="http://example.com/documents"><Name>hello.txt</Name><Content>
+		aGVsbG8g
+		d29ybGQ=
+	</Content></Document>`
+	if err := xml.Unmarshal([]byte(wrapped), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if string(decoded.Content) != "hello world" {
+		t.Errorf("Expected 'hello world', got: %q", decoded.Content)
+	}
+}
+
+func TestClient_MTOMRequest(t *testing.T) {
+	t.Parallel()
+	doc := testDocument()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if !strings.HasPrefix(contentType, "multipart/related;") ||
+			!strings.Contains(contentType, `type="application/xop+xml"`) {
+			t.Errorf("Expected MTOM content type, got: %s", contentType)
+		}
+		if got := r.Header.Get("SOAPAction"); got != "urn:Upload" {
+			t.Errorf("Expected SOAPAction 'urn:Upload', got: %s", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		root, parts, err := decodeMultipartRelated(contentType, body)
+		if err != nil {
+			t.Fatalf("Failed to decode MTOM request: %v", err)
+		}
+		if !strings.Contains(string(root.data), `<xop:Include xmlns:xop="http://www.w3.org/2004/08/xop/include"`) {
+			t.Errorf("Expected xop:Include in root part: %s", root.data)
+		}
+		if len(parts) != 1 || !bytes.Equal(parts[0].data, doc.Content) {
+			t.Fatalf("Expected one binary part with the document content, got %d parts", len(parts))
+		}
+		resolved, remaining, err := resolveXOP(root.data, parts)
+		if err != nil {
+			t.Fatalf("Failed to resolve XOP: %v", err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("Expected all parts to be referenced, got %d remaining", len(remaining))
+		}
+		var env Envelope
+		if err := xml.Unmarshal(resolved, &env); err != nil {
+			t.Fatalf("Failed to unmarshal resolved envelope: %v", err)
+		}
+		var received documentPayload
+		if err := xml.Unmarshal(env.Body.Content, &received); err != nil {
+			t.Fatalf("Failed to unmarshal document: %v", err)
+		}
+		if !bytes.Equal(received.Content, doc.Content) {
+			t.Error("Resolved document content does not match")
+		}
+		respEnv, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithMTOM(true), WithXOPElements("Document/Content"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, err := NewEnvelope(WithBody(doc))
+	if err != nil {
+		t.Fatalf("Failed to create envelope: %v", err)
+	}
+	if _, err := client.Call(context.Background(), "urn:Upload", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+}
+
+func TestClient_MTOMResponse(t *testing.T) {
+	t.Parallel()
+	doc := testDocument()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respEnv, _ := NewEnvelope(WithBody(doc))
+		respXML, _ := xml.Marshal(respEnv)
+		body, contentType, err := encodeMultipartRelated(respXML, Version11, "", true, []string{"Document/Content"}, nil)
+		if err != nil {
+			t.Fatalf("Failed to encode MTOM response: %v", err)
+		}
+		if !bytes.Contains(body, []byte("xop:Include")) {
+			t.Fatal("Expected the response to be XOP-optimized")
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<Download/>`)))
+	respEnv, err := client.Call(context.Background(), "", reqEnv)
+	if err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	var result documentPayload
+	if err := xml.Unmarshal(respEnv.Body.Content, &result); err != nil {
+		t.Fatalf("Failed to unmarshal document: %v", err)
+	}
+	if result.Name != "report.pdf" {
+		t.Errorf("Expected name 'report.pdf', got: %s", result.Name)
+	}
+	if !bytes.Equal(result.Content, doc.Content) {
+		t.Error("Expected XOP include to be resolved into the document content")
+	}
+}
+
+func TestOptimizeXOP(t *testing.T) {
+	t.Parallel()
+	binary := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0xff, 0x00, 0x7f}, 512))
+	tests := []struct {
+		name      string
+		body      string
+		wantParts int
+	}{
+		{name: "declared element", body: `<Document><Name>a</Name><Content>` + binary + `</Content></Document>`, wantParts: 1},
+		{name: "small content", body: `<Document><Content>aGVsbG8gd29ybGQ=</Content></Document>`},
+		{name: "text that looks like base64", body: `<Document><Name>` + binary + `</Name></Document>`},
+		{name: "element of another path", body: `<Archive><Content>` + binary + `</Content></Archive>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			xmlData := []byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+				tt.body + `</soapenv:Body></soapenv:Envelope>`)
+			root, parts, err := optimizeXOP(xmlData, []string{"Document/Content"})
+			if err != nil {
+				t.Fatalf("optimizeXOP() error = %v", err)
+			}
+			if len(parts) != tt.wantParts {
+				t.Fatalf("Expected %d parts, got: %d", tt.wantParts, len(parts))
+			}
+			if tt.wantParts == 0 && !bytes.Equal(root, xmlData) {
+				t.Errorf("Expected XML to be unchanged, got: %s", root)
+			}
+			if tt.wantParts == 1 && !bytes.Contains(root, []byte(`<Content><xop:Include`)) {
+				t.Errorf("Expected the content to be replaced by an xop:Include, got: %s", root)
+			}
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package soap
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// WithNTLMAuth authenticates requests with NTLMv2 authentication, as used by
+// Microsoft services such as IIS, SharePoint and Exchange. Servers offering
+// the Negotiate scheme are answered with NTLM as well.
+//
+// NTLM authenticates connections rather than requests: a request is first
+// sent as is, and only when the server challenges it does the client perform
+// the handshake. Each handshake runs on a dedicated HTTP/1.1 connection,
+// which later requests reuse without a handshake once it is idle. The
+// transport of [WithHTTPClient] must be an [*http.Transport], or a
+// [DebugTransport] with one as Next.
+func WithNTLMAuth(domain, username, password string) ClientOption {
+	auth := &ntlmAuth{domain: domain, username: username, password: password}
+	return func(c *clientConfig) {
+		c.auth = auth
+	}
+}
+
+// ntlmAuth holds the credentials of NTLM authentication, and the idle
+// connections authenticated with them.
+type ntlmAuth struct {
+	domain   string
+	username string
+	password string
+	mu       sync.Mutex
+	idle     map[ntlmConnKey][]*ntlmConn
+}
+
+// ntlmConnKey identifies the connections to a host through a base transport.
+type ntlmConnKey struct {
+	base http.RoundTripper
+	host string
+}
+
+// ntlmConn is a transport with a single HTTP/1.1 connection, through a copy
+// of the base transport and its wrappers.
+type ntlmConn struct {
+	transport http.RoundTripper
+	conn      *http.Transport
+}
+
+// ntlmMaxIdleConns is the number of idle authenticated connections kept per
+// host, like the default of [http.Transport].
+const ntlmMaxIdleConns = http.DefaultMaxIdleConnsPerHost
+
+// NTLM message flags, see [MS-NLMP] section 2.2.2.5.
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmNegotiateOEM                     = 0x00000002
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiateFlags                   = ntlmNegotiateUnicode | ntlmNegotiateOEM | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign | ntlmNegotiateExtendedSessionSecurity
+)
+
+// ntlmAvTimestamp is the AvId of the server time in target information.
+const ntlmAvTimestamp uint16 = 7
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+func (a *ntlmAuth) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	switch next.(type) {
+	case *http.Transport, *DebugTransport:
+	default:
+		return nil, fmt.Errorf("NTLM authentication requires an *http.Transport, got %T", next)
+	}
+	key := ntlmConnKey{base: next, host: req.URL.Scheme + "://" + req.URL.Host}
+	conn := a.takeIdle(key)
+	if conn == nil {
+		var err error
+		if conn, err = newNTLMConn(next); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := a.handshake(conn.transport, req)
+	if err != nil {
+		conn.conn.CloseIdleConnections()
+		return nil, err
+	}
+	resp.Body = &ntlmBody{ReadCloser: resp.Body, release: func() { a.putIdle(key, conn) }}
+	return resp, nil
+}
+
+// handshake sends a request on a single connection, and authenticates the
+// connection when the server challenges the request.
+func (a *ntlmAuth) handshake(conn http.RoundTripper, req *http.Request) (*http.Response, error) {
+	resp, err := conn.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	scheme := ntlmScheme(resp)
+	if scheme == "" {
+		return resp, nil
+	}
+	discardResponse(resp)
+
+	// Negotiate, without the request body
+	leg := req.Clone(req.Context())
+	leg.Body, leg.GetBody, leg.ContentLength = http.NoBody, nil, 0
+	leg.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(ntlmNegotiateMessage()))
+	resp, err = conn.RoundTrip(leg)
+	if err != nil {
+		return nil, err
+	}
+	challenges := authChallenges(resp, scheme)
+	if len(challenges) == 0 || challenges[0] == "" {
+		return resp, nil
+	}
+	challenge, err := base64.StdEncoding.DecodeString(challenges[0])
+	discardResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NTLM challenge: %w", err)
+	}
+
+	// Authenticate
+	authenticate, err := a.authenticateMessage(challenge, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if leg, err = authLeg(req, false); err != nil {
+		return nil, err
+	}
+	leg.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(authenticate))
+	return conn.RoundTrip(leg)
+}
+
+// takeIdle returns an idle connection to a host, or nil if there is none.
+func (a *ntlmAuth) takeIdle(key ntlmConnKey) *ntlmConn {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	conns := a.idle[key]
+	if len(conns) == 0 {
+		return nil
+	}
+	conn := conns[len(conns)-1]
+	a.idle[key] = conns[:len(conns)-1]
+	return conn
+}
+
+// putIdle keeps a connection whose response is done for later requests, or
+// closes it if enough connections to the host are idle.
+func (a *ntlmAuth) putIdle(key ntlmConnKey, conn *ntlmConn) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.idle[key]) >= ntlmMaxIdleConns {
+		conn.conn.CloseIdleConnections()
+		return
+	}
+	if a.idle == nil {
+		a.idle = make(map[ntlmConnKey][]*ntlmConn)
+	}
+	a.idle[key] = append(a.idle[key], conn)
+}
+
+// newNTLMConn returns a copy of a base transport limited to a single HTTP/1.1
+// connection per host. NTLM does not work over HTTP/2, which multiplexes the
+// requests of different callers on a connection.
+func newNTLMConn(base http.RoundTripper) (*ntlmConn, error) {
+	switch base := base.(type) {
+	case *http.Transport:
+		transport := base.Clone()
+		transport.MaxConnsPerHost = 1
+		transport.MaxIdleConnsPerHost = 1
+		transport.ForceAttemptHTTP2 = false
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+		if config := transport.TLSClientConfig; config != nil {
+			config = config.Clone()
+			config.NextProtos = slices.DeleteFunc(slices.Clone(config.NextProtos), func(proto string) bool { return proto == "h2" })
+			transport.TLSClientConfig = config
+		}
+		return &ntlmConn{transport: transport, conn: transport}, nil
+	case *DebugTransport:
+		conn, err := newNTLMConn(base.next())
+		if err != nil {
+			return nil, err
+		}
+		debug := *base
+		debug.Next = conn.transport
+		return &ntlmConn{transport: &debug, conn: conn.conn}, nil
+	default:
+		return nil, fmt.Errorf("NTLM authentication requires an *http.Transport, got %T", base)
+	}
+}
+
+// ntlmBody releases the connection of a response once its body is closed.
+type ntlmBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements [io.Closer].
+func (b *ntlmBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// ntlmScheme returns the scheme of the NTLM challenge of a response, NTLM or
+// Negotiate, or the empty string if the response is not challenged.
+func ntlmScheme(resp *http.Response) string {
+	for _, scheme := range []string{"NTLM", "Negotiate"} {
+		for _, challenge := range authChallenges(resp, scheme) {
+			if challenge == "" {
+				return scheme
+			}
+		}
+	}
+	return ""
+}
+
+// ntlmNegotiateMessage returns a NEGOTIATE_MESSAGE without domain and
+// workstation.
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+	return msg
+}
+
+// authenticateMessage returns the AUTHENTICATE_MESSAGE answering a
+// CHALLENGE_MESSAGE with an NTLMv2 response.
+func (a *ntlmAuth) authenticateMessage(challenge []byte, now time.Time) ([]byte, error) {
+	if len(challenge) < 48 || !bytes.Equal(challenge[:8], ntlmSignature) ||
+		binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return nil, fmt.Errorf("invalid NTLM challenge message")
+	}
+	flags := binary.LittleEndian.Uint32(challenge[20:])
+	serverChallenge := challenge[24:32]
+	targetInfo, err := ntlmPayload(challenge, 40)
+	if err != nil {
+		return nil, err
+	}
+	var clientChallenge [8]byte
+	if _, err := rand.Read(clientChallenge[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate NTLM client challenge: %w", err)
+	}
+
+	// The server timestamp takes precedence, in which case there is no LMv2
+	// response
+	timestamp, hasTimestamp := ntlmAvPair(targetInfo, ntlmAvTimestamp)
+	if !hasTimestamp {
+		timestamp = binary.LittleEndian.AppendUint64(nil, ntlmFileTime(now))
+	}
+	key := ntowfv2(a.domain, a.username, a.password)
+	ntResponse := ntlmv2Response(key, serverChallenge, clientChallenge[:], timestamp, targetInfo)
+	lmResponse := make([]byte, 24)
+	if !hasTimestamp {
+		lmResponse = append(hmacMD5(key, serverChallenge, clientChallenge[:]), clientChallenge[:]...)
+	}
+
+	unicode := flags&ntlmNegotiateUnicode != 0
+	payloads := [][]byte{
+		lmResponse,
+		ntResponse,
+		ntlmString(a.domain, unicode),
+		ntlmString(a.username, unicode),
+		nil, // Workstation
+		nil, // EncryptedRandomSessionKey
+	}
+	const headerSize = 64
+	msg := make([]byte, headerSize)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	for i, payload := range payloads {
+		field := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(field, uint16(len(payload)))
+		binary.LittleEndian.PutUint16(field[2:], uint16(len(payload)))
+		binary.LittleEndian.PutUint32(field[4:], uint32(len(msg)))
+		msg = append(msg, payload...)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags&(ntlmNegotiateFlags|ntlmNegotiateTargetInfo))
+	return msg, nil
+}
+
+// ntlmPayload returns the payload of a message field at an offset.
+func ntlmPayload(msg []byte, offset int) ([]byte, error) {
+	length := int(binary.LittleEndian.Uint16(msg[offset:]))
+	start := int(binary.LittleEndian.Uint32(msg[offset+4:]))
+	if start+length > len(msg) {
+		return nil, fmt.Errorf("invalid NTLM challenge message")
+	}
+	return msg[start : start+length], nil
+}
+
+// ntlmAvPair returns the value of an AV_PAIR of target information.
+func ntlmAvPair(targetInfo []byte, id uint16) ([]byte, bool) {
+	for len(targetInfo) >= 4 {
+		avID := binary.LittleEndian.Uint16(targetInfo)
+		length := int(binary.LittleEndian.Uint16(targetInfo[2:]))
+		if avID == 0 || 4+length > len(targetInfo) {
+			break
+		}
+		if avID == id {
+			return targetInfo[4 : 4+length], true
+		}
+		targetInfo = targetInfo[4+length:]
+	}
+	return nil, false
+}
+
+// ntlmFileTime returns a time as the number of 100ns intervals since January
+// 1, 1601.
+func ntlmFileTime(t time.Time) uint64 {
+	const epochDelta = 116444736000000000
+	return uint64(t.UnixNano()/100) + epochDelta
+}
+
+// ntlmString encodes a string of a message.
+func ntlmString(s string, unicode bool) []byte {
+	if !unicode {
+		return []byte(s)
+	}
+	return utf16le(s)
+}
+
+// ntowfv2 returns the NTLMv2 response key of a user.
+func ntowfv2(domain, username, password string) []byte {
+	hash := md4(utf16le(password))
+	return hmacMD5(hash[:], utf16le(strings.ToUpper(username)+domain))
+}
+
+// ntlmv2Response returns the NTLMv2 response to a server challenge.
+func ntlmv2Response(key, serverChallenge, clientChallenge, timestamp, targetInfo []byte) []byte {
+	var temp []byte
+	temp = append(temp, 1, 1, 0, 0, 0, 0, 0, 0)
+	temp = append(temp, timestamp...)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+	return append(hmacMD5(key, serverChallenge, temp), temp...)
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+func utf16le(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
+
+// md4 returns the MD4 digest of data (RFC 1320), which NTLM uses to hash
+// passwords and the standard library does not provide.
+func md4(data []byte) [16]byte {
+	msg := append(bytes.Clone(data), 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, uint64(len(data))*8)
+
+	s := [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	var x [16]uint32
+	for ; len(msg) > 0; msg = msg[64:] {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[4*i:])
+		}
+		a, b, c, d := s[0], s[1], s[2], s[3]
+		for i := range 16 {
+			f := (b & c) | (^b & d)
+			a, b, c, d = d, bits.RotateLeft32(a+f+x[i], []int{3, 7, 11, 19}[i%4]), b, c
+		}
+		for i := range 16 {
+			g := (b & c) | (b & d) | (c & d)
+			k := i%4*4 + i/4
+			a, b, c, d = d, bits.RotateLeft32(a+g+x[k]+0x5a827999, []int{3, 5, 9, 13}[i%4]), b, c
+		}
+		for i, k := range [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15} {
+			h := b ^ c ^ d
+			a, b, c, d = d, bits.RotateLeft32(a+h+x[k]+0x6ed9eba1, []int{3, 9, 11, 15}[i%4]), b, c
+		}
+		s[0] += a
+		s[1] += b
+		s[2] += c
+		s[3] += d
+	}
+	var sum [16]byte
+	for i, v := range s {
+		binary.LittleEndian.PutUint32(sum[4*i:], v)
+	}
+	return sum
+}
@@ -0,0 +1,213 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMD4(t *testing.T) {
+	t.Parallel()
+	// Test suite of RFC 1320
+	tests := map[string]string{
+		"":               "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"abc":            "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest": "d9130a8164549fe818874806e1c7014b",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, want := range tests {
+		if sum := md4([]byte(input)); hex.EncodeToString(sum[:]) != want {
+			t.Errorf("md4(%q) = %x, want %s", input, sum, want)
+		}
+	}
+}
+
+func TestNTLMv2Response(t *testing.T) {
+	t.Parallel()
+	// Test vectors of [MS-NLMP] section 4.2.4
+	key := ntowfv2("Domain", "User", "Password")
+	if got := hex.EncodeToString(key); got != "0c868a403bfd7a93a3001ef22ef02e3f" {
+		t.Errorf("ntowfv2() = %s", got)
+	}
+	serverChallenge, _ := hex.DecodeString("0123456789abcdef")
+	clientChallenge := bytes.Repeat([]byte{0xaa}, 8)
+	targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+	response := ntlmv2Response(key, serverChallenge, clientChallenge, make([]byte, 8), targetInfo)
+	if got := hex.EncodeToString(response[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("ntlmv2Response() NTProofStr = %s", got)
+	}
+}
+
+// newNTLMServer starts a test server requiring NTLM authentication of its
+// connections, over TLS with HTTP/2 enabled if http2 is true. It counts the
+// handshakes.
+func newNTLMServer(t *testing.T, password string, http2 bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var handshakes atomic.Int32
+	var mu sync.Mutex
+	authenticated := make(map[string]bool)
+	challenged := make(map[string][]byte)
+	echo := newEchoHandler()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if authenticated[r.RemoteAddr] {
+			echo.ServeHTTP(w, r)
+			return
+		}
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "NTLM ")
+		msg, _ := base64.StdEncoding.DecodeString(token)
+		// A request of another caller on the connection breaks the handshake
+		serverChallenge := challenged[r.RemoteAddr]
+		delete(challenged, r.RemoteAddr)
+		switch {
+		case r.ProtoMajor != 1:
+			// Like IIS, refuse NTLM over HTTP/2
+			w.WriteHeader(http.StatusHTTPVersionNotSupported)
+			return
+		case len(msg) >= 12 && binary.LittleEndian.Uint32(msg[8:]) == 1 && r.ContentLength == 0:
+			handshakes.Add(1)
+			serverChallenge := []byte("\x01\x23\x45\x67\x89\xab\xcd\xef")
+			challenged[r.RemoteAddr] = serverChallenge
+			targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0000000000")
+			challenge := make([]byte, 48)
+			copy(challenge, ntlmSignature)
+			binary.LittleEndian.PutUint32(challenge[8:], 2)
+			binary.LittleEndian.PutUint32(challenge[20:], ntlmNegotiateFlags|ntlmNegotiateTargetInfo)
+			copy(challenge[24:], serverChallenge)
+			binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint32(challenge[44:], 48)
+			challenge = append(challenge, targetInfo...)
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(challenge))
+		case len(msg) >= 64 && binary.LittleEndian.Uint32(msg[8:]) == 3:
+			// The authenticate message must come on the challenged connection
+			ntResponse, _ := ntlmPayload(msg, 20)
+			domain, _ := ntlmPayload(msg, 28)
+			user, _ := ntlmPayload(msg, 36)
+			key := ntowfv2("Domain", "User", password)
+			if serverChallenge != nil && len(ntResponse) > 16 &&
+				bytes.Equal(domain, utf16le("Domain")) && bytes.Equal(user, utf16le("User")) &&
+				bytes.Equal(ntResponse[:16], hmacMD5(key, serverChallenge, ntResponse[16:])) {
+				authenticated[r.RemoteAddr] = true
+				echo.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "NTLM")
+		default:
+			w.Header().Set("WWW-Authenticate", "Negotiate")
+			w.Header().Add("WWW-Authenticate", "NTLM")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	if http2 {
+		server.EnableHTTP2 = true
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+	return server, &handshakes
+}
+
+func TestClient_NTLMAuth(t *testing.T) {
+	t.Parallel()
+	t.Run("connection reuse", func(t *testing.T) {
+		t.Parallel()
+		server, handshakes := newNTLMServer(t, "Password", false)
+		client, err := NewClient(
+			WithEndpoint(server.URL),
+			WithHTTPClient(&http.Client{Transport: &http.Transport{MaxConnsPerHost: 1}}),
+			WithNTLMAuth("Domain", "User", "Password"),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		for _, message := range []string{"hello", "world"} {
+			reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: message}))
+			var resp echoResponse
+			if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp); err != nil {
+				t.Fatalf("Client.CallDecode() error = %v", err)
+			}
+			if resp.Message != message {
+				t.Errorf("Expected message %q, got: %q", message, resp.Message)
+			}
+		}
+		if got := handshakes.Load(); got != 1 {
+			t.Errorf("Expected 1 handshake on the reused connection, got: %d", got)
+		}
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		t.Parallel()
+		server, handshakes := newNTLMServer(t, "Password", false)
+		client, err := NewClient(WithEndpoint(server.URL), WithNTLMAuth("Domain", "User", "Password"))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Go(func() {
+				message := fmt.Sprintf("hello %d", i)
+				reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: message}))
+				var resp echoResponse
+				if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp); err != nil {
+					t.Errorf("Client.CallDecode() error = %v", err)
+					return
+				}
+				if resp.Message != message {
+					t.Errorf("Expected message %q, got: %q", message, resp.Message)
+				}
+			})
+		}
+		wg.Wait()
+		if got := handshakes.Load(); got < 1 || got > 20 {
+			t.Errorf("Expected between 1 and 20 handshakes, got: %d", got)
+		}
+	})
+
+	t.Run("HTTP/2 server", func(t *testing.T) {
+		t.Parallel()
+		server, _ := newNTLMServer(t, "Password", true)
+		roots := x509.NewCertPool()
+		roots.AddCert(server.Certificate())
+		client, err := NewClient(WithEndpoint(server.URL), WithRootCAs(roots), WithNTLMAuth("Domain", "User", "Password"))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		var resp echoResponse
+		if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp); err != nil {
+			t.Fatalf("Client.CallDecode() error = %v", err)
+		}
+		if resp.Message != "hello" {
+			t.Errorf("Expected message %q, got: %q", "hello", resp.Message)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		t.Parallel()
+		server, _ := newNTLMServer(t, "Password", false)
+		client, err := NewClient(WithEndpoint(server.URL), WithNTLMAuth("Domain", "User", "wrong"))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv)
+		var soapErr *Error
+		if !errors.As(err, &soapErr) || soapErr.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("Expected an HTTP 401 error, got: %v", err)
+		}
+	})
+}
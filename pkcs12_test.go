@@ -0,0 +1,54 @@
+package soap
+
+import (
+	"crypto/ecdsa"
+	"os"
+	"strings"
+	"testing"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestParsePKCS12(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		file     string
+		password string
+		wantErr  string
+	}{
+		{name: "PBES2 with AES", file: "testdata/client.p12", password: "changeit"},
+		{name: "legacy 3DES", file: "testdata/client-3des.p12", password: "changeit"},
+		{name: "legacy RC2", file: "testdata/client-rc2.p12", password: "changeit"},
+		{name: "wrong password", file: "testdata/client.p12", password: "wrong", wantErr: pkcs12.ErrIncorrectPassword.Error()},
+		{name: "not a bundle", file: "testdata/client.pem", password: "changeit", wantErr: "pkcs12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tt.file, err)
+			}
+			certificate, err := parsePKCS12(data, tt.password)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePKCS12() error = %v", err)
+			}
+			if _, ok := certificate.PrivateKey.(*ecdsa.PrivateKey); !ok {
+				t.Errorf("Expected an ECDSA private key, got: %T", certificate.PrivateKey)
+			}
+			if certificate.Leaf == nil || certificate.Leaf.Subject.CommonName != "Test Client" {
+				t.Errorf("Expected the client certificate as leaf, got: %v", certificate.Leaf)
+			}
+			if len(certificate.Certificate) != 2 {
+				t.Errorf("Expected the leaf and CA certificates, got: %d", len(certificate.Certificate))
+			}
+		})
+	}
+}
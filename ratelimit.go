@@ -0,0 +1,126 @@
+package soap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// rateLimit is a token bucket applying to requests, all of them or those with
+// a SOAP action or endpoint.
+type rateLimit struct {
+	action   string
+	endpoint string
+	bucket   *tokenBucket
+}
+
+// matches reports whether the rate limit applies to a request.
+func (l *rateLimit) matches(req *http.Request) bool {
+	return (l.action == "" || l.action == httpRequestAction(req)) &&
+		(l.endpoint == "" || l.endpoint == req.URL.String())
+}
+
+// WithRateLimit limits the requests of the client to perSecond on average,
+// with bursts of up to burst requests. perSecond must be positive and burst
+// at least 1, or [NewClient] and calls fail. Every attempt counts, including
+// retries. Calls wait for their turn, or fail with the context error when
+// their context ends first.
+//
+// The limit is shared by the calls of a client created with it; as a call
+// option, it only limits the attempts of that call.
+func WithRateLimit(perSecond float64, burst int) ClientOption {
+	return withRateLimit(rateLimit{}, perSecond, burst)
+}
+
+// WithActionRateLimit limits the requests with a SOAP action, like
+// [WithRateLimit]. It applies in addition to the limits of all requests.
+func WithActionRateLimit(action string, perSecond float64, burst int) ClientOption {
+	return withRateLimit(rateLimit{action: action}, perSecond, burst)
+}
+
+// WithEndpointRateLimit limits the requests to an endpoint URL, like
+// [WithRateLimit]. With an endpoint pool, each endpoint can have its own limit.
+func WithEndpointRateLimit(endpoint string, perSecond float64, burst int) ClientOption {
+	return withRateLimit(rateLimit{endpoint: endpoint}, perSecond, burst)
+}
+
+// withRateLimit returns an option adding a rate limit with a new token bucket
+// each time it is applied, or failing the configuration if the rate or burst
+// is not positive.
+func withRateLimit(limit rateLimit, perSecond float64, burst int) ClientOption {
+	if !(perSecond > 0) {
+		return withError(fmt.Errorf("invalid rate limit of %v requests per second: must be positive", perSecond))
+	}
+	if burst < 1 {
+		return withError(fmt.Errorf("invalid rate limit burst %d: must be at least 1", burst))
+	}
+	return func(c *clientConfig) {
+		limit := limit
+		limit.bucket = newTokenBucket(perSecond, burst)
+		c.rateLimits = append(slices.Clip(c.rateLimits), &limit)
+	}
+}
+
+// tokenBucket is a token bucket rate limiter. Tokens go negative for waiting
+// requests, which reserve the tokens refilled before their turn.
+type tokenBucket struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+func newTokenBucket(perSecond float64, burst int) *tokenBucket {
+	return &tokenBucket{perSecond: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, waiting until one is available or the context ends.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.perSecond)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.perSecond * float64(time.Second))
+	}
+	b.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	if err := sleepWithContext(ctx, delay); err != nil {
+		// Give the reserved token back to the requests waiting behind
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rateLimitTransport waits for the rate limits of each attempt.
+type rateLimitTransport struct {
+	limits []*rateLimit
+	next   http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, limit := range t.limits {
+		if !limit.matches(req) {
+			continue
+		}
+		if err := limit.bucket.wait(req.Context()); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
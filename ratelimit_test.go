@@ -0,0 +1,81 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	t.Parallel()
+	server, _ := newCountingServer(t, http.StatusOK)
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithRateLimit(1000, 10),
+		WithActionRateLimit("urn:limited", 20, 1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	elapsed := func(action string, calls int) time.Duration {
+		start := time.Now()
+		for range calls {
+			if _, err := client.Call(context.Background(), action, reqEnv); err != nil {
+				t.Fatalf("Client.Call() error = %v", err)
+			}
+		}
+		return time.Since(start)
+	}
+	if d := elapsed("urn:limited", 3); d < 90*time.Millisecond {
+		t.Errorf("Expected 3 calls at 20/s to take at least 100ms, took %v", d)
+	}
+	if d := elapsed("urn:other", 5); d > 90*time.Millisecond {
+		t.Errorf("Expected other actions not to wait for the action limit, took %v", d)
+	}
+	// The limited action has no token left, so the call waits past its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _ = client.Call(context.Background(), "urn:limited", reqEnv)
+	if _, err := client.Call(ctx, "urn:limited", reqEnv); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error while waiting, got: %v", err)
+	}
+}
+
+func TestNewClient_InvalidRateLimit(t *testing.T) {
+	t.Parallel()
+	for _, opt := range []ClientOption{
+		WithRateLimit(0, 1),
+		WithRateLimit(-1, 1),
+		WithActionRateLimit("urn:limited", 10, 0),
+		WithEndpointRateLimit("http://example.com", math.NaN(), 1),
+	} {
+		if _, err := NewClient(opt); err == nil || !strings.Contains(err.Error(), "invalid rate limit") {
+			t.Errorf("Expected an invalid rate limit error, got: %v", err)
+		}
+	}
+}
+
+func TestClient_RateLimitOptionReused(t *testing.T) {
+	t.Parallel()
+	server, _ := newCountingServer(t, http.StatusOK)
+	limit := WithRateLimit(5, 1)
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	start := time.Now()
+	for range 2 {
+		client, err := NewClient(WithEndpoint(server.URL), limit)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.Call(context.Background(), "urn:test", reqEnv); err != nil {
+			t.Fatalf("Client.Call() error = %v", err)
+		}
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("Expected clients created with the same option not to share a bucket, took %v", d)
+	}
+}
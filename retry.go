@@ -3,14 +3,15 @@ package soap
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"math/rand"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,23 +19,35 @@ type retryTransport struct {
 	maxRetries  int
 	next        http.RoundTripper
 	shouldRetry func(context.Context, error, *http.Request, *http.Response) bool
+	backoff     Backoff
+	maxElapsed  time.Duration
+	budget      *RetryBudget
 }
 
 func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// if body is present, it must be buffered if there is any chance of a retry
 	// since it can only be consumed once.
-	var br *bytes.Reader
-	if req.Body != nil && req.Body != http.NoBody {
-		var buf bytes.Buffer
-		if _, err := io.Copy(&buf, req.Body); err != nil {
-			_ = req.Body.Close()
+	var body []byte
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
 			return nil, fmt.Errorf("error buffering body before retry: %w", err)
 		}
-		_ = req.Body.Close()
-		br = bytes.NewReader(buf.Bytes())
-		req.Body = io.NopCloser(br)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		// Let inner transports replay the body within an attempt, such as
+		// authentication handshakes and endpoint failover
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
 	}
+	encode, _ := req.Context().Value(requestEncoderKey{}).(requestEncoder)
+	start := time.Now()
+	t.budget.deposit()
 	var attemptCount int
+	var delay time.Duration
 	for {
 		res, err := t.next.RoundTrip(req)
 		attemptCount++
@@ -50,13 +63,14 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		if !shouldRetryResult {
 			return res, err
 		}
-		delay := retryDelay(attemptCount, res)
-		if br != nil {
-			if _, serr := br.Seek(0, 0); serr != nil {
-				return res, fmt.Errorf("error seeking body buffer back to beginning after attempt: %w", serr)
-			}
-			req.Body = io.NopCloser(br)
+		delay = retryDelay(t.backoff, attemptCount, delay, res)
+		if t.maxElapsed > 0 && time.Since(start)+delay > t.maxElapsed {
+			return res, err
+		}
+		if !t.budget.withdraw() {
+			return res, err
 		}
+		callTracerFrom(req.Context()).retryScheduled(req.Context(), delay, res, err)
 		if res != nil {
 			_, _ = io.Copy(io.Discard, res.Body)
 			_ = res.Body.Close()
@@ -64,6 +78,19 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		if err := sleepWithContext(req.Context(), delay); err != nil {
 			return nil, err
 		}
+		if hasBody && encode != nil {
+			// Encode the body again, such as for a fresh WS-Security header
+			data, mediaType, err := encode()
+			if err != nil {
+				return nil, err
+			}
+			body = data
+			req.ContentLength = int64(len(body))
+			req.Header.Set("Content-Type", mediaType)
+		}
+		if hasBody {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
 	}
 }
 
@@ -78,6 +105,11 @@ func isTimeoutErr(err error) bool {
 }
 
 // DefaultCheckRetry implements the default retry logic for HTTP requests.
+//
+// DNS errors and rate limited responses are always retried. Timeouts and
+// server errors are retried for idempotent requests only, see [WithIdempotent],
+// unless the response is a SOAP fault other than a Server (SOAP 1.2: Receiver)
+// fault.
 func DefaultCheckRetry(ctx context.Context, err error, request *http.Request, response *http.Response) bool {
 	select {
 	case <-ctx.Done():
@@ -97,13 +129,15 @@ func DefaultCheckRetry(ctx context.Context, err error, request *http.Request, re
 		http.StatusServiceUnavailable,
 		http.StatusGatewayTimeout,
 		http.StatusInternalServerError:
-		return isIdempotent(request)
+		return isIdempotent(request) && !hasPermanentFault(response)
 	default:
 		return false
 	}
 }
 
-func retryDelay(attempt int, response *http.Response) time.Duration {
+// retryDelay returns the delay before retrying a failed attempt, from the
+// Retry-After header of the response or the backoff policy.
+func retryDelay(backoff Backoff, attempt int, previous time.Duration, response *http.Response) time.Duration {
 	if response != nil {
 		if retryAfter := response.Header.Get("Retry-After"); retryAfter != "" {
 			if i, err := strconv.Atoi(retryAfter); err == nil {
@@ -114,18 +148,10 @@ func retryDelay(attempt int, response *http.Response) time.Duration {
 			}
 		}
 	}
-	return expBackoff(attempt)
-}
-
-func expBackoff(attempt int) time.Duration {
-	// based on "full jitter": https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
-	const base = time.Millisecond * 250
-	const cap = time.Second * 10
-	exp := math.Pow(2, float64(attempt-1))
-	v := float64(base) * exp
-	return time.Duration(
-		rand.Int63n(int64(math.Min(float64(cap), v))),
-	)
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+	return backoff.Delay(attempt, previous)
 }
 
 func addJitter(d time.Duration) time.Duration {
@@ -140,7 +166,13 @@ func addJitter(d time.Duration) time.Duration {
 	return time.Duration(f - j)
 }
 
+// idempotentKey is the context key marking requests of idempotent operations.
+type idempotentKey struct{}
+
 func isIdempotent(req *http.Request) bool {
+	if idempotent, _ := req.Context().Value(idempotentKey{}).(bool); idempotent {
+		return true
+	}
 	if req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != "" {
 		return true
 	}
@@ -151,6 +183,45 @@ func isIdempotent(req *http.Request) bool {
 	return false
 }
 
+// faultPeekBytes is the maximum size of a response body inspected for a SOAP fault.
+const faultPeekBytes = 64 << 10
+
+// hasPermanentFault reports whether the response carries a SOAP fault that
+// retrying will not resolve: any fault but Server (SOAP 1.2: Receiver)
+// faults, which report a failure of the service rather than of the request.
+func hasPermanentFault(response *http.Response) bool {
+	fault := peekFault(response)
+	return fault != nil && !isServerFault(fault)
+}
+
+// peekFault returns the SOAP fault carried by the response, if any. The
+// response body is left readable from the start.
+func peekFault(response *http.Response) *Fault {
+	if response.Body == nil || response.Body == http.NoBody {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, faultPeekBytes))
+	response.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), response.Body), response.Body}
+	if err != nil {
+		return nil
+	}
+	var env Envelope
+	if err := xml.Unmarshal(data, &env); err != nil {
+		return nil
+	}
+	return checkForSOAPFault(&env)
+}
+
+// isServerFault reports whether a fault has a Server (SOAP 1.2: Receiver)
+// code, including SOAP 1.1 dotted subcodes like Server.Database.
+func isServerFault(fault *Fault) bool {
+	code, _, _ := strings.Cut(localName(fault.FaultCode), ".")
+	return code == "Server" || code == "Receiver"
+}
+
 func sleepWithContext(ctx context.Context, duration time.Duration) error {
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/xml"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -334,3 +336,72 @@ func TestClient_WithCheckRetry(t *testing.T) {
 		t.Errorf("Unexpected response body: %s", string(respEnv.Body.Content))
 	}
 }
+
+func TestDefaultCheckRetry_Faults(t *testing.T) {
+	t.Parallel()
+	fault := func(code string) string {
+		return `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+			`<soapenv:Fault><faultcode>` + code + `</faultcode><faultstring>failed</faultstring></soapenv:Fault>` +
+			`</soapenv:Body></soapenv:Envelope>`
+	}
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{name: "server fault", body: fault("soapenv:Server"), expected: true},
+		{name: "server subcode fault", body: fault("soapenv:Server.Database"), expected: true},
+		{name: "client fault", body: fault("soapenv:Client"), expected: false},
+		{name: "application fault", body: fault("app:InvalidAccount"), expected: false},
+		{name: "not a fault", body: "Internal Server Error", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.WithValue(context.Background(), idempotentKey{}, true)
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com", nil)
+			resp := &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			if got := DefaultCheckRetry(ctx, nil, req, resp); got != tt.expected {
+				t.Errorf("DefaultCheckRetry() = %v, want %v", got, tt.expected)
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+				t.Errorf("Expected the response body to be preserved, got: %s", body)
+			}
+		})
+	}
+}
+
+func TestClient_WithIdempotent(t *testing.T) {
+	t.Parallel()
+	var requestCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestCount.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(1))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	if _, err := client.Call(context.Background(), "urn:get", reqEnv, WithIdempotent(true)); err != nil {
+		t.Fatalf("Expected success after retrying an idempotent call, got: %v", err)
+	}
+	if got := requestCount.Load(); got != 2 {
+		t.Errorf("Expected 2 requests for the idempotent call, got %d", got)
+	}
+	if _, err := client.Call(context.Background(), "urn:update", reqEnv); err == nil {
+		t.Fatal("Expected the non-idempotent call to fail without retry")
+	}
+	if got := requestCount.Load(); got != 3 {
+		t.Errorf("Expected 1 request for the non-idempotent call, got %d", got-2)
+	}
+}
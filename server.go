@@ -0,0 +1,333 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// HandlerFunc handles a SOAP request envelope and returns the response envelope.
+//
+// Returning a nil envelope without error acknowledges a one-way request with
+// HTTP 202 Accepted and no body. Errors are sent as SOAP faults: a [*Fault]
+// anywhere in the error chain is sent as is, other errors as a Server
+// (SOAP 1.2: Receiver) fault with the error message as fault string.
+type HandlerFunc func(ctx context.Context, request *Envelope) (*Envelope, error)
+
+// Server is an [http.Handler] serving SOAP operations.
+//
+// Requests are dispatched to the handler registered for their SOAP action,
+// taken from the SOAPAction header or the action parameter of the SOAP 1.2
+// content type. Requests without a registered action are dispatched by the
+// qualified name of the first element in the SOAP body.
+//
+// Both SOAP 1.1 and SOAP 1.2 requests are served, including multipart/related
+// (MTOM and SOAP with Attachments) requests. Responses use the SOAP version of
+// the request.
+type Server struct {
+	config   serverConfig
+	mu       sync.RWMutex
+	actions  map[string]HandlerFunc
+	elements map[xml.Name]HandlerFunc
+}
+
+// ServerOption configures a Server using the functional options pattern.
+type ServerOption func(*serverConfig)
+
+// serverConfig holds the configuration for a Server.
+type serverConfig struct {
+	maxRequestBytes int64
+}
+
+// WithMaxRequestBytes limits the size of request bodies read by the server.
+// Larger requests are rejected with HTTP 413. Zero, the default, means no limit.
+func WithMaxRequestBytes(n int64) ServerOption {
+	return func(c *serverConfig) {
+		c.maxRequestBytes = n
+	}
+}
+
+// NewServer creates a new SOAP server with the specified options.
+func NewServer(opts ...ServerOption) *Server {
+	var config serverConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Server{
+		config:   config,
+		actions:  make(map[string]HandlerFunc),
+		elements: make(map[xml.Name]HandlerFunc),
+	}
+}
+
+// HandleFunc registers the handler for requests with the given SOAP action or
+// body element. An empty action or element is not used for dispatch. It panics
+// if both are empty, or if a handler is already registered for either.
+func (s *Server) HandleFunc(action string, element xml.Name, handler HandlerFunc) {
+	if action == "" && element.Local == "" {
+		panic("soap: handler requires an action or a body element")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.actions[action]; ok && action != "" {
+		panic(fmt.Sprintf("soap: multiple handlers for action %q", action))
+	}
+	if _, ok := s.elements[element]; ok && element.Local != "" {
+		panic(fmt.Sprintf("soap: multiple handlers for element {%s}%s", element.Space, element.Local))
+	}
+	if action != "" {
+		s.actions[action] = handler
+	}
+	if element.Local != "" {
+		s.elements[element] = handler
+	}
+}
+
+// Handle registers a typed handler on the server. The first element of the
+// request body is decoded into Req, and the returned Resp is marshalled as the
+// response body, both using [encoding/xml] semantics. A nil Resp acknowledges
+// a one-way request.
+//
+// Requests are dispatched by the given SOAP action, and by the element name
+// declared by the XMLName field tag of Req, if any. It panics under the same
+// conditions as [Server.HandleFunc].
+func Handle[Req, Resp any](s *Server, action string, handler func(ctx context.Context, request *Req) (*Resp, error)) {
+	element := typeElementName(reflect.TypeFor[Req]())
+	s.HandleFunc(action, element, func(ctx context.Context, request *Envelope) (*Envelope, error) {
+		var req Req
+		if err := request.DecodeBody(&req); err != nil {
+			return nil, &Fault{FaultCode: "Client", FaultString: err.Error()}
+		}
+		resp, err := handler(ctx, &req)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		version := Version11
+		if request.namespace() == Namespace12 {
+			version = Version12
+		}
+		return NewEnvelope(WithVersion(version), WithBody(resp))
+	})
+}
+
+// ServeHTTP implements [http.Handler].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "SOAP requests must use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	contentType := r.Header.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !isSOAPMediaType(mediaType, params) {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	body := io.Reader(r.Body)
+	if s.config.maxRequestBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.config.maxRequestBytes)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	version := Version11
+	if mediaType == "application/soap+xml" || strings.Contains(params["type"], "application/soap+xml") {
+		version = Version12
+	}
+	var request Envelope
+	envelopeXML, attachments, err := decodeResponseBody(contentType, data)
+	if err == nil {
+		err = xml.Unmarshal(envelopeXML, &request)
+		request.Attachments = attachments
+	}
+	if err != nil {
+		writeFault(w, version, &Fault{FaultCode: "Client", FaultString: "invalid SOAP request: " + err.Error()})
+		return
+	}
+	switch request.namespace() {
+	case Namespace:
+		version = Version11
+	case Namespace12:
+		version = Version12
+	default:
+		writeFault(w, version, &Fault{FaultCode: "VersionMismatch", FaultString: "unsupported SOAP envelope namespace"})
+		return
+	}
+	handler, ok := s.handler(requestAction(r, mediaType, params), &request)
+	if !ok {
+		writeFault(w, version, &Fault{FaultCode: "Client", FaultString: "no handler for the SOAP request"})
+		return
+	}
+	response, err := handler(r.Context(), &request)
+	if err != nil {
+		fault := &Fault{FaultCode: "Server", FaultString: err.Error()}
+		_ = errors.As(err, &fault)
+		writeFault(w, version, fault)
+		return
+	}
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeEnvelope(w, version, http.StatusOK, response)
+}
+
+// handler returns the handler for a request, by action first and body element second.
+func (s *Server) handler(action string, request *Envelope) (HandlerFunc, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if handler, ok := s.actions[action]; ok && action != "" {
+		return handler, true
+	}
+	element, ok := request.BodyElementName()
+	if !ok {
+		return nil, false
+	}
+	handler, ok := s.elements[element]
+	return handler, ok
+}
+
+// isSOAPMediaType reports whether a request media type carries a SOAP envelope.
+func isSOAPMediaType(mediaType string, params map[string]string) bool {
+	switch mediaType {
+	case "text/xml", "application/soap+xml":
+		return true
+	case "multipart/related":
+		return params["type"] == "application/xop+xml" || params["type"] == "text/xml" ||
+			params["type"] == "application/soap+xml"
+	}
+	return false
+}
+
+// requestAction returns the SOAP action of a request, from the SOAPAction
+// header (SOAP 1.1) or the action media type parameter (SOAP 1.2).
+func requestAction(r *http.Request, mediaType string, params map[string]string) string {
+	if action := strings.Trim(r.Header.Get("SOAPAction"), `"`); action != "" {
+		return action
+	}
+	if action := params["action"]; action != "" {
+		return action
+	}
+	// MTOM requests carry the SOAP 1.2 action in the start-info parameter
+	if mediaType == "multipart/related" {
+		if _, startParams, err := mime.ParseMediaType(params["start-info"]); err == nil {
+			return startParams["action"]
+		}
+	}
+	return ""
+}
+
+// typeElementName returns the element name declared by the XMLName field tag of a struct type.
+func typeElementName(t reflect.Type) xml.Name {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return xml.Name{}
+	}
+	field, ok := t.FieldByName("XMLName")
+	if !ok || field.Type != reflect.TypeFor[xml.Name]() {
+		return xml.Name{}
+	}
+	tag, _, _ := strings.Cut(field.Tag.Get("xml"), ",")
+	if space, local, ok := strings.Cut(tag, " "); ok {
+		return xml.Name{Space: space, Local: local}
+	}
+	return xml.Name{Local: tag}
+}
+
+// writeFault writes a SOAP fault response for the SOAP version. SOAP 1.2
+// Sender faults use HTTP 400, all other faults HTTP 500.
+func writeFault(w http.ResponseWriter, version Version, fault *Fault) {
+	fault = fault.forVersion(version)
+	statusCode := http.StatusInternalServerError
+	if version == Version12 && localName(fault.Code.Value) == "Sender" {
+		statusCode = http.StatusBadRequest
+	}
+	response, err := NewEnvelope(WithVersion(version), WithBody(fault))
+	if err != nil {
+		http.Error(w, "failed to encode SOAP fault", http.StatusInternalServerError)
+		return
+	}
+	writeEnvelope(w, version, statusCode, response)
+}
+
+// writeEnvelope writes a SOAP response envelope.
+func writeEnvelope(w http.ResponseWriter, version Version, statusCode int, env *Envelope) {
+	data, err := xml.Marshal(env)
+	if err != nil {
+		http.Error(w, "failed to encode SOAP response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType(version, ""))
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(addXMLDeclaration(data))
+}
+
+// faultCodes12 maps SOAP 1.1 fault codes to their SOAP 1.2 equivalents.
+var faultCodes12 = map[string]string{"Client": "Sender", "Server": "Receiver"}
+
+// forVersion returns a copy of the fault with the fields of the SOAP version
+// populated from their counterparts, so it encodes in that version's layout.
+func (f *Fault) forVersion(version Version) *Fault {
+	result := *f
+	result.XMLName = xml.Name{Space: version.Namespace(), Local: "Fault"}
+	if version == Version12 {
+		if result.Code == nil {
+			code := localName(f.FaultCode)
+			if code12, ok := faultCodes12[code]; ok {
+				code = code12
+			}
+			if slices.Contains(standardFaultCodes, code) {
+				result.Code = &Code{Value: code}
+			} else {
+				// SOAP 1.2 only allows standard codes, application codes become subcodes
+				result.Code = &Code{Value: "Receiver", Subcode: &Code{Value: f.FaultCode}}
+			}
+		}
+		if result.Reason == nil {
+			result.Reason = &Reason{Texts: []ReasonText{{Lang: "en", Value: f.FaultString}}}
+		}
+		if result.Node == "" {
+			result.Node = f.FaultActor
+		}
+		return &result
+	}
+	if result.FaultCode == "" && f.Code != nil {
+		result.FaultCode = f.Code.Value
+	}
+	for code11, code12 := range faultCodes12 {
+		if localName(result.FaultCode) == code12 {
+			result.FaultCode = code11
+		}
+	}
+	if result.FaultString == "" {
+		result.FaultString = f.Reason.Text("en")
+	}
+	if result.FaultActor == "" {
+		result.FaultActor = f.Node
+	}
+	return &result
+}
+
+// localName returns the local part of a qualified name.
+func localName(qualifiedName string) string {
+	if _, local, ok := strings.Cut(qualifiedName, ":"); ok {
+		return local
+	}
+	return qualifiedName
+}
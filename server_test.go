@@ -0,0 +1,180 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type echoRequest struct {
+	XMLName xml.Name `xml:"http://example.com/echo Echo"`
+	Message string   `xml:"Message"`
+}
+
+type echoResponse struct {
+	XMLName xml.Name `xml:"http://example.com/echo EchoResponse"`
+	Message string   `xml:"Message"`
+}
+
+// newEchoServer starts a test server with an echo operation and a failing operation.
+func newEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := NewServer()
+	Handle(server, "urn:echo", func(ctx context.Context, req *echoRequest) (*echoResponse, error) {
+		switch req.Message {
+		case "invalid":
+			return nil, &Fault{FaultCode: "Client", FaultString: "invalid message"}
+		case "fail":
+			return nil, errors.New("backend unavailable")
+		}
+		return &echoResponse{Message: req.Message}, nil
+	})
+	server.HandleFunc("urn:notify", xml.Name{}, func(ctx context.Context, req *Envelope) (*Envelope, error) {
+		return nil, nil
+	})
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func TestServer_Dispatch(t *testing.T) {
+	t.Parallel()
+	httpServer := newEchoServer(t)
+	tests := []struct {
+		name    string
+		action  string
+		version Version
+	}{
+		{name: "SOAP 1.1 by action", action: "urn:echo", version: Version11},
+		{name: "SOAP 1.1 by body element", version: Version11},
+		{name: "SOAP 1.2 by action", action: "urn:echo", version: Version12},
+		{name: "SOAP 1.2 by body element", version: Version12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := NewClient(WithEndpoint(httpServer.URL), WithSOAPVersion(tt.version), WithMaxRetries(0))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			reqEnv, _ := NewEnvelope(WithVersion(tt.version), WithBody(&echoRequest{Message: "hello"}))
+			var resp echoResponse
+			respEnv, err := client.CallDecode(context.Background(), tt.action, reqEnv, &resp)
+			if err != nil {
+				t.Fatalf("Client.CallDecode() error = %v", err)
+			}
+			if resp.Message != "hello" {
+				t.Errorf("Expected echoed message, got: %q", resp.Message)
+			}
+			if respEnv.XMLName.Space != tt.version.Namespace() {
+				t.Errorf("Expected response namespace %s, got: %s", tt.version.Namespace(), respEnv.XMLName.Space)
+			}
+		})
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	t.Parallel()
+	httpServer := newEchoServer(t)
+	tests := []struct {
+		name       string
+		version    Version
+		message    string
+		statusCode int
+		faultCode  string
+		reason     string
+	}{
+		{
+			name:       "SOAP 1.1 client fault",
+			version:    Version11,
+			message:    "invalid",
+			statusCode: http.StatusInternalServerError,
+			faultCode:  "soapenv:Client",
+			reason:     "invalid message",
+		},
+		{
+			name:       "SOAP 1.1 error",
+			version:    Version11,
+			message:    "fail",
+			statusCode: http.StatusInternalServerError,
+			faultCode:  "soapenv:Server",
+			reason:     "backend unavailable",
+		},
+		{
+			name:       "SOAP 1.2 sender fault",
+			version:    Version12,
+			message:    "invalid",
+			statusCode: http.StatusBadRequest,
+			faultCode:  "soapenv:Sender",
+			reason:     "invalid message",
+		},
+		{
+			name:       "SOAP 1.2 error",
+			version:    Version12,
+			message:    "fail",
+			statusCode: http.StatusInternalServerError,
+			faultCode:  "soapenv:Receiver",
+			reason:     "backend unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := NewClient(WithEndpoint(httpServer.URL), WithSOAPVersion(tt.version), WithMaxRetries(0))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			reqEnv, _ := NewEnvelope(WithVersion(tt.version), WithBody(&echoRequest{Message: tt.message}))
+			_, err = client.Call(context.Background(), "urn:echo", reqEnv)
+			var soapErr *Error
+			if !errors.As(err, &soapErr) || soapErr.Fault == nil {
+				t.Fatalf("Expected a SOAP fault, got: %v", err)
+			}
+			if soapErr.StatusCode != tt.statusCode {
+				t.Errorf("Expected HTTP %d, got %d", tt.statusCode, soapErr.StatusCode)
+			}
+			if soapErr.Fault.FaultCode != tt.faultCode || soapErr.Fault.FaultString != tt.reason {
+				t.Errorf("Unexpected fault: %v", soapErr.Fault)
+			}
+		})
+	}
+}
+
+func TestServer_HTTP(t *testing.T) {
+	t.Parallel()
+	httpServer := newEchoServer(t)
+	post := func(contentType, action, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("SOAPAction", action)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+	envelope := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><Other/></soapenv:Body></soapenv:Envelope>`
+	if resp := post("text/xml", `"urn:notify"`, envelope); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected HTTP 202 for a one-way request, got %d", resp.StatusCode)
+	}
+	if resp := post("text/xml", "", envelope); resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected HTTP 500 for an unknown operation, got %d", resp.StatusCode)
+	}
+	if resp := post("application/json", "", `{}`); resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected HTTP 415 for a non-SOAP content type, got %d", resp.StatusCode)
+	}
+	resp, err := http.Get(httpServer.URL)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected HTTP 405 for GET, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,78 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// NewSlogTrace returns a [ClientTrace] that logs the lifecycle of calls to
+// the handler, with the action, endpoint, HTTP status, fault code and
+// attempt count as structured attributes.
+//
+// Attempts and responses are logged at debug level, retries at warn level,
+// and finished calls at info level, or error level when the call failed.
+func NewSlogTrace(handler slog.Handler) *ClientTrace {
+	logger := slog.New(handler)
+	return &ClientTrace{
+		AttemptStart: func(ctx context.Context, info AttemptStartInfo) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "SOAP attempt started",
+				slog.String("soap.action", info.Action),
+				slog.String("soap.endpoint", info.Endpoint),
+				slog.Int("soap.attempt", info.Attempt),
+			)
+		},
+		ResponseReceived: func(ctx context.Context, info ResponseReceivedInfo) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "SOAP response received",
+				slog.String("soap.action", info.Action),
+				slog.String("soap.endpoint", info.Endpoint),
+				slog.Int("soap.attempt", info.Attempt),
+				slog.Int("http.status_code", info.StatusCode),
+			)
+		},
+		RetryScheduled: func(ctx context.Context, info RetryScheduledInfo) {
+			attrs := []slog.Attr{
+				slog.String("soap.action", info.Action),
+				slog.String("soap.endpoint", info.Endpoint),
+				slog.Int("soap.attempt", info.Attempt),
+				slog.Duration("soap.retry_delay", info.Delay),
+			}
+			if info.StatusCode != 0 {
+				attrs = append(attrs, slog.Int("http.status_code", info.StatusCode))
+			}
+			if info.Err != nil {
+				attrs = append(attrs, slog.String("error", info.Err.Error()))
+			}
+			logger.LogAttrs(ctx, slog.LevelWarn, "SOAP retry scheduled", attrs...)
+		},
+		FaultDecoded: func(ctx context.Context, info FaultDecodedInfo) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "SOAP fault received",
+				slog.String("soap.action", info.Action),
+				slog.String("soap.endpoint", info.Endpoint),
+				slog.Int("http.status_code", info.StatusCode),
+				slog.String("soap.fault_code", info.Fault.FaultCode),
+				slog.String("soap.fault_string", info.Fault.FaultString),
+			)
+		},
+		CallDone: func(ctx context.Context, info CallDoneInfo) {
+			attrs := []slog.Attr{
+				slog.String("soap.action", info.Action),
+				slog.String("soap.endpoint", info.Endpoint),
+				slog.Int("soap.attempts", info.Attempts),
+				slog.Duration("duration", info.Duration),
+			}
+			if info.StatusCode != 0 {
+				attrs = append(attrs, slog.Int("http.status_code", info.StatusCode))
+			}
+			if info.Err == nil {
+				logger.LogAttrs(ctx, slog.LevelInfo, "SOAP call finished", attrs...)
+				return
+			}
+			if soapErr := (*Error)(nil); errors.As(info.Err, &soapErr) && soapErr.Fault != nil {
+				attrs = append(attrs, slog.String("soap.fault_code", soapErr.Fault.FaultCode))
+			}
+			attrs = append(attrs, slog.String("error", info.Err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "SOAP call failed", attrs...)
+		},
+	}
+}
@@ -0,0 +1,404 @@
+package soaptest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNoInteraction is returned by a replaying [Recorder] for requests that
+// match no recorded interaction.
+var ErrNoInteraction = errors.New("soaptest: no recorded interaction matches the request")
+
+// Mode is the operating mode of a [Recorder].
+type Mode int
+
+const (
+	// ModeReplay serves recorded interactions and never reaches the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the network and records the interactions,
+	// replacing the cassette.
+	ModeRecord
+	// ModeRecordOnce records when the cassette file does not exist, and
+	// replays otherwise.
+	ModeRecordOnce
+)
+
+// DefaultIgnoredElements are the elements whose content is ignored when
+// matching request bodies: values that change on every request, like
+// WS-Addressing message IDs, WS-Security timestamps, nonces and digests.
+var DefaultIgnoredElements = []xml.Name{
+	{Space: "http://www.w3.org/2005/08/addressing", Local: "MessageID"},
+	{Space: "http://schemas.xmlsoap.org/ws/2004/08/addressing", Local: "MessageID"},
+	{Space: "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd", Local: "Created"},
+	{Space: "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd", Local: "Expires"},
+	{Space: "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd", Local: "Nonce"},
+	{Space: "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd", Local: "Password"},
+	{Space: "http://www.w3.org/2000/09/xmldsig#", Local: "DigestValue"},
+	{Space: "http://www.w3.org/2000/09/xmldsig#", Local: "SignatureValue"},
+}
+
+// sensitiveHeaders are the response headers whose values are masked in
+// cassettes.
+var sensitiveHeaders = []string{
+	"Set-Cookie",
+	"Authorization",
+	"Proxy-Authorization",
+	"Authentication-Info",
+	"Proxy-Authentication-Info",
+}
+
+// maskedValue replaces masked element content and header values in cassettes.
+const maskedValue = "[REDACTED]"
+
+// Recorder records SOAP interactions to a cassette file and replays them.
+//
+// A Recorder plugs into a client as an interceptor:
+//
+//	recorder, err := soaptest.NewRecorder("testdata/cassette.json", soaptest.ModeReplay)
+//	client, err := soap.NewClient(soap.WithEndpoint(url), soap.WithInterceptor(recorder.Interceptor))
+//
+// In replay mode, requests are matched against the recorded interactions by
+// HTTP method, endpoint URL, SOAP action and request body. Bodies are compared
+// as by [EqualXML], ignoring the content of the [DefaultIgnoredElements] and
+// of elements added with [IgnoreElements]. Each recorded interaction is
+// served once, in recording order.
+//
+// Cassettes are meant to be committed, so the content of the ignored elements,
+// which include WS-Security passwords and nonces, is masked in recorded request
+// bodies, as are the values of sensitive response headers such as Set-Cookie.
+type Recorder struct {
+	file     string
+	mode     Mode
+	ignored  []xml.Name
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+}
+
+// RecorderOption configures a Recorder using the functional options pattern.
+type RecorderOption func(*Recorder)
+
+// IgnoreElements ignores the content of additional elements when matching
+// request bodies.
+func IgnoreElements(names ...xml.Name) RecorderOption {
+	return func(r *Recorder) {
+		r.ignored = append(r.ignored, names...)
+	}
+}
+
+// cassette is the on-disk format of recorded interactions.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+// interaction is a recorded request and its response.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+// recordedRequest is a recorded HTTP request.
+type recordedRequest struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Action      string `json:"action,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	recordedBody
+}
+
+// recordedResponse is a recorded HTTP response.
+type recordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+// recordedBody is an HTTP body, stored as text when it is valid UTF-8 and
+// as base64 otherwise.
+type recordedBody struct {
+	Body     string `json:"body"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// newRecordedBody returns the recorded form of an HTTP body.
+func newRecordedBody(data []byte) recordedBody {
+	if utf8.Valid(data) {
+		return recordedBody{Body: string(data)}
+	}
+	return recordedBody{Body: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+// bytes returns the content of the body.
+func (b recordedBody) bytes() ([]byte, error) {
+	if b.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// NewRecorder creates a recorder for the cassette file. In replay mode the
+// cassette is loaded immediately, and an error is returned if it cannot be read.
+func NewRecorder(file string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{file: file, mode: mode, ignored: slices.Clone(DefaultIgnoredElements)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.mode == ModeRecordOnce {
+		r.mode = ModeReplay
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", file, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder, [ModeRecord] or [ModeReplay].
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Interceptor wraps the next round tripper to record or replay requests.
+// It is meant for use with soap.WithInterceptor.
+func (r *Recorder) Interceptor(next http.RoundTripper) http.RoundTripper {
+	return &recorderTransport{recorder: r, next: next}
+}
+
+// recorderTransport is the round tripper returned by Recorder.Interceptor.
+type recorderTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	request := recordedRequest{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		Action:       requestAction(req),
+		ContentType:  req.Header.Get("Content-Type"),
+		recordedBody: newRecordedBody(body),
+	}
+	if t.recorder.mode == ModeReplay {
+		return t.recorder.replay(req, request)
+	}
+	request.recordedBody = newRecordedBody(maskBody(request.ContentType, body, t.recorder.ignored))
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err := t.recorder.record(interaction{
+		Request: request,
+		Response: recordedResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       maskHeader(resp.Header),
+			recordedBody: newRecordedBody(respBody),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// record appends an interaction to the cassette and saves it.
+func (r *Recorder) record(i interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.file, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// replay returns the response of the first unused interaction matching the request.
+func (r *Recorder) replay(req *http.Request, request recordedRequest) (*http.Response, error) {
+	canonical, err := r.canonicalBody(request)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, recorded := range r.cassette.Interactions {
+		if r.used[i] || recorded.Request.Method != request.Method ||
+			recorded.Request.URL != request.URL || recorded.Request.Action != request.Action {
+			continue
+		}
+		if recordedCanonical, err := r.canonicalBody(recorded.Request); err != nil || recordedCanonical != canonical {
+			continue
+		}
+		body, err := recorded.Response.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recorded response body: %w", err)
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode:    recorded.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s with action %q in %s", ErrNoInteraction, request.Method, request.URL, request.Action, r.file)
+}
+
+// canonicalBody returns the canonical form of a request's SOAP envelope used
+// for matching. Bodies that are not XML are compared as is.
+func (r *Recorder) canonicalBody(request recordedRequest) (string, error) {
+	body, err := request.bytes()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode recorded request body: %w", err)
+	}
+	body = rootPart(request.ContentType, body)
+	if canonical, err := canonicalXML(body, r.ignored...); err == nil {
+		return canonical, nil
+	}
+	return string(body), nil
+}
+
+// rootPart returns the root part of a multipart/related body, which holds
+// the SOAP envelope, so that matching does not depend on the random boundary.
+func rootPart(contentType string, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/related" {
+		return body
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return body
+		}
+		if start := params["start"]; start == "" || strings.Trim(part.Header.Get("Content-ID"), "<>") == strings.Trim(start, "<>") {
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return body
+			}
+			return data
+		}
+	}
+}
+
+// maskBody returns a copy of a request body with the content of the masked
+// elements of its SOAP envelope replaced. Bodies that are not XML are kept.
+func maskBody(contentType string, body []byte, masked []xml.Name) []byte {
+	root := rootPart(contentType, body)
+	maskedRoot, err := maskElements(root, masked)
+	if err != nil {
+		return body
+	}
+	i := bytes.Index(body, root)
+	if i < 0 {
+		// The root part is encoded; keep only the masked envelope
+		return maskedRoot
+	}
+	return slices.Concat(body[:i], maskedRoot, body[i+len(root):])
+}
+
+// maskElements returns a copy of an XML document with the content of the
+// masked elements replaced, keeping the rest of the document as written.
+func maskElements(data []byte, masked []xml.Name) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var b bytes.Buffer
+	written := int64(0)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || !slices.Contains(masked, start.Name) {
+			continue
+		}
+		contentStart := d.InputOffset()
+		contentEnd := contentStart
+		for depth := 1; depth > 0; {
+			contentEnd = d.InputOffset()
+			token, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch token.(type) {
+			case xml.StartElement:
+				depth++
+			case xml.EndElement:
+				depth--
+			}
+		}
+		if contentEnd > contentStart {
+			b.Write(data[written:contentStart])
+			b.WriteString(maskedValue)
+			written = contentEnd
+		}
+	}
+	b.Write(data[written:])
+	return b.Bytes(), nil
+}
+
+// maskHeader returns a copy of a response header with the values of the
+// sensitive headers masked.
+func maskHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if values := result.Values(name); len(values) > 0 {
+			result[http.CanonicalHeaderKey(name)] = slices.Repeat([]string{maskedValue}, len(values))
+		}
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package soaptest
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	soap "github.com/way-platform/soap-go"
+)
+
+type timedQuoteRequest struct {
+	XMLName   xml.Name `xml:"http://example.com/quotes GetQuote"`
+	Symbol    string   `xml:"Symbol"`
+	Timestamp string   `xml:"Timestamp"`
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "cassettes", "quotes.json")
+	ignoreTimestamp := IgnoreElements(xml.Name{Space: "http://example.com/quotes", Local: "Timestamp"})
+	call := func(recorder *Recorder, endpoint, symbol, timestamp string) (*quoteResponse, error) {
+		t.Helper()
+		client, err := soap.NewClient(
+			soap.WithEndpoint(endpoint),
+			soap.WithMaxRetries(0),
+			soap.WithInterceptor(recorder.Interceptor),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := soap.NewEnvelope(soap.WithBody(&timedQuoteRequest{Symbol: symbol, Timestamp: timestamp}))
+		var resp quoteResponse
+		if _, err := client.CallDecode(context.Background(), "urn:GetQuote", reqEnv, &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+
+	// Record an interaction against a live endpoint
+	server := NewServer(t)
+	server.ExpectAction("urn:GetQuote").Reply(&quoteResponse{Price: "42.00"}).Times(1)
+	recorder, err := NewRecorder(file, ModeRecordOnce, ignoreTimestamp)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if recorder.Mode() != ModeRecord {
+		t.Fatalf("Expected record mode without a cassette, got %v", recorder.Mode())
+	}
+	if _, err := call(recorder, server.URL, "WAY", "2026-01-01T00:00:00Z"); err != nil {
+		t.Fatalf("Recording call failed: %v", err)
+	}
+
+	// Replay it without the endpoint, with a different timestamp
+	recorder, err = NewRecorder(file, ModeRecordOnce, ignoreTimestamp)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if recorder.Mode() != ModeReplay {
+		t.Fatalf("Expected replay mode with a cassette, got %v", recorder.Mode())
+	}
+	resp, err := call(recorder, server.URL, "WAY", "2026-06-30T12:00:00Z")
+	if err != nil {
+		t.Fatalf("Replayed call failed: %v", err)
+	}
+	if resp.Price != "42.00" {
+		t.Errorf("Expected recorded price, got: %q", resp.Price)
+	}
+
+	// Interactions are served once, and other requests do not match
+	if _, err := call(recorder, server.URL, "WAY", "2026-06-30T12:00:00Z"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction for a replayed interaction, got: %v", err)
+	}
+	recorder, _ = NewRecorder(file, ModeReplay, ignoreTimestamp)
+	if _, err := call(recorder, server.URL, "ACME", "2026-06-30T12:00:00Z"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction for a different body, got: %v", err)
+	}
+	recorder, _ = NewRecorder(file, ModeReplay)
+	if _, err := call(recorder, server.URL, "WAY", "2026-06-30T12:00:00Z"); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction without ignored timestamp, got: %v", err)
+	}
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("Expected an error for a missing cassette in replay mode")
+	}
+}
+
+func TestRecorder_MasksSecrets(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "cassette.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t-cookie"})
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	call := func(recorder *Recorder, password string) error {
+		t.Helper()
+		client, err := soap.NewClient(
+			soap.WithEndpoint(server.URL),
+			soap.WithUsernameToken("alice", password, soap.PasswordText),
+			soap.WithInterceptor(recorder.Interceptor),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := soap.NewEnvelope(soap.WithBody(&timedQuoteRequest{Symbol: "WAY"}))
+		_, err = client.Call(context.Background(), "urn:GetQuote", reqEnv)
+		return err
+	}
+	recorder, err := NewRecorder(file, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if err := call(recorder, "hunter2"); err != nil {
+		t.Fatalf("Recording call failed: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	for _, secret := range []string{"hunter2", "s3cr3t-cookie"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be masked in the cassette:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "alice") {
+		t.Errorf("Expected the rest of the request to be recorded:\n%s", data)
+	}
+	recorder, err = NewRecorder(file, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if err := call(recorder, "other"); err != nil {
+		t.Errorf("Replayed call failed: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package soaptest provides utilities for testing SOAP clients: an in-process
+// fake SOAP endpoint with expectations, a record/replay interceptor for
+// recorded interactions, and namespace-aware XML comparison.
+package soaptest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	soap "github.com/way-platform/soap-go"
+)
+
+// Server is a fake SOAP endpoint for tests.
+//
+// Requests are matched against the registered expectations in registration
+// order, by SOAP action or by the qualified name of the first body element.
+// Requests matching no expectation fail the test and are answered with a
+// Client fault. Every request is recorded for later assertions.
+type Server struct {
+	// URL is the endpoint URL of the server.
+	URL string
+
+	t            testing.TB
+	httpServer   *httptest.Server
+	mu           sync.Mutex
+	expectations []*Expectation
+	requests     []Request
+}
+
+// Request is a request received by a [Server].
+type Request struct {
+	// Action is the SOAP action of the request, from the SOAPAction header or
+	// the action parameter of the SOAP 1.2 content type.
+	Action string
+
+	// Header is the HTTP header of the request.
+	Header http.Header
+
+	// Body is the raw HTTP request body.
+	Body []byte
+
+	// Envelope is the decoded request envelope.
+	Envelope *soap.Envelope
+}
+
+// NewServer starts a fake SOAP endpoint. The server is closed when the test
+// ends, and the test fails if an expectation was not met.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{t: t}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.httpServer.URL
+	t.Cleanup(func() {
+		s.httpServer.Close()
+		s.verify()
+	})
+	return s
+}
+
+// ExpectAction registers an expectation for requests with the given SOAP action.
+func (s *Server) ExpectAction(action string) *Expectation {
+	return s.expect(&Expectation{action: action})
+}
+
+// ExpectElement registers an expectation for requests whose first body
+// element has the given qualified name.
+func (s *Server) ExpectElement(name xml.Name) *Expectation {
+	return s.expect(&Expectation{element: name})
+}
+
+// expect registers an expectation.
+func (s *Server) expect(e *Expectation) *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expectations = append(s.expectations, e)
+	return e
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Expectation is an expected request and the reply to send for it. By
+// default an expectation must match at least once, and is answered with an
+// empty body.
+type Expectation struct {
+	action  string
+	element xml.Name
+	reply   any
+	fault   *soap.Fault
+	times   int
+	calls   int
+}
+
+// Reply sets the body of the response. v is marshalled with [xml.Marshal];
+// a []byte value is used as raw XML.
+func (e *Expectation) Reply(v any) *Expectation {
+	e.reply = v
+	return e
+}
+
+// ReplyFault sets the SOAP fault to respond with. The fault is sent in the
+// layout of the request's SOAP version, with HTTP status 500, or 400 for
+// SOAP 1.2 Sender faults.
+func (e *Expectation) ReplyFault(fault *soap.Fault) *Expectation {
+	e.fault = fault
+	return e
+}
+
+// Times sets the exact number of requests the expectation must match.
+// Further requests fall through to later expectations.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// matches reports whether the expectation matches the request.
+func (e *Expectation) matches(action string, element xml.Name) bool {
+	if e.times > 0 && e.calls >= e.times {
+		return false
+	}
+	if e.action != "" {
+		return e.action == action
+	}
+	return e.element == element
+}
+
+// String returns a description of the expectation for failure messages.
+func (e *Expectation) String() string {
+	if e.action != "" {
+		return fmt.Sprintf("action %q", e.action)
+	}
+	return fmt.Sprintf("element {%s}%s", e.element.Space, e.element.Local)
+}
+
+// serveHTTP records the request and replies per the matching expectation.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("soaptest: failed to read request body: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var env soap.Envelope
+	if err := xml.Unmarshal(body, &env); err != nil {
+		s.t.Errorf("soaptest: failed to decode request envelope: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	version := soap.Version11
+	if env.XMLName.Space == soap.Namespace12 {
+		version = soap.Version12
+	}
+	action := requestAction(r)
+	element, _ := env.BodyElementName()
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Action: action, Header: r.Header.Clone(), Body: body, Envelope: &env})
+	var expectation *Expectation
+	for _, e := range s.expectations {
+		if e.matches(action, element) {
+			e.calls++
+			expectation = e
+			break
+		}
+	}
+	s.mu.Unlock()
+	if expectation == nil {
+		s.t.Errorf("soaptest: unexpected request with action %q and element {%s}%s", action, element.Space, element.Local)
+		writeFault(w, version, &soap.Fault{FaultCode: "Client", FaultString: "unexpected request"})
+		return
+	}
+	if expectation.fault != nil {
+		writeFault(w, version, expectation.fault)
+		return
+	}
+	response, err := soap.NewEnvelope(soap.WithVersion(version), soap.WithBody(expectation.reply))
+	if err != nil {
+		s.t.Errorf("soaptest: failed to encode reply for %v: %v", expectation, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeEnvelope(w, version, http.StatusOK, response)
+}
+
+// verify fails the test for expectations that were not met.
+func (s *Server) verify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.expectations {
+		switch {
+		case e.times > 0 && e.calls != e.times:
+			s.t.Errorf("soaptest: expected %d requests for %v, got %d", e.times, e, e.calls)
+		case e.times == 0 && e.calls == 0:
+			s.t.Errorf("soaptest: expected a request for %v, got none", e)
+		}
+	}
+}
+
+// requestAction returns the SOAP action of a request.
+func requestAction(r *http.Request) string {
+	if action := strings.Trim(r.Header.Get("SOAPAction"), `"`); action != "" {
+		return action
+	}
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return params["action"]
+}
+
+// writeFault writes a SOAP fault response in the layout of the SOAP version.
+func writeFault(w http.ResponseWriter, version soap.Version, fault *soap.Fault) {
+	fault = versionedFault(fault, version)
+	statusCode := http.StatusInternalServerError
+	if version == soap.Version12 && strings.HasSuffix(fault.Code.Value, "Sender") {
+		statusCode = http.StatusBadRequest
+	}
+	response, err := soap.NewEnvelope(soap.WithVersion(version), soap.WithBody(fault))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeEnvelope(w, version, statusCode, response)
+}
+
+// versionedFault returns a copy of the fault encoding in the SOAP version's layout.
+func versionedFault(fault *soap.Fault, version soap.Version) *soap.Fault {
+	result := *fault
+	result.XMLName = xml.Name{Space: version.Namespace(), Local: "Fault"}
+	if version == soap.Version12 && result.Code == nil {
+		code := fault.FaultCode
+		switch code {
+		case "Client":
+			code = "Sender"
+		case "Server":
+			code = "Receiver"
+		}
+		result.Code = &soap.Code{Value: code}
+		result.Reason = &soap.Reason{Texts: []soap.ReasonText{{Lang: "en", Value: fault.FaultString}}}
+	}
+	return &result
+}
+
+// writeEnvelope writes a SOAP response envelope.
+func writeEnvelope(w http.ResponseWriter, version soap.Version, statusCode int, env *soap.Envelope) {
+	data, err := xml.Marshal(env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType := "text/xml; charset=utf-8"
+	if version == soap.Version12 {
+		contentType = "application/soap+xml; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(append([]byte(xml.Header), data...))
+}
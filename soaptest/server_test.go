@@ -0,0 +1,112 @@
+package soaptest
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+
+	soap "github.com/way-platform/soap-go"
+)
+
+type quoteRequest struct {
+	XMLName xml.Name `xml:"http://example.com/quotes GetQuote"`
+	Symbol  string   `xml:"Symbol"`
+}
+
+type quoteResponse struct {
+	XMLName xml.Name `xml:"http://example.com/quotes GetQuoteResponse"`
+	Price   string   `xml:"Price"`
+}
+
+// recordingT records test failures and cleanups instead of acting on them.
+type recordingT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+// finish runs the recorded cleanups.
+func (r *recordingT) finish() {
+	for _, f := range r.cleanups {
+		f()
+	}
+}
+
+func TestServer_Reply(t *testing.T) {
+	t.Parallel()
+	server := NewServer(t)
+	server.ExpectAction("urn:GetQuote").Reply(&quoteResponse{Price: "42.00"}).Times(1)
+	server.ExpectElement(xml.Name{Space: "http://example.com/quotes", Local: "GetQuote"}).
+		ReplyFault(&soap.Fault{FaultCode: "Client", FaultString: "unknown symbol"})
+	for _, version := range []soap.Version{soap.Version11, soap.Version12} {
+		client, err := soap.NewClient(soap.WithEndpoint(server.URL), soap.WithSOAPVersion(version), soap.WithMaxRetries(0))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := soap.NewEnvelope(soap.WithVersion(version), soap.WithBody(&quoteRequest{Symbol: "WAY"}))
+		if version == soap.Version11 {
+			var resp quoteResponse
+			if _, err := client.CallDecode(context.Background(), "urn:GetQuote", reqEnv, &resp); err != nil {
+				t.Fatalf("Client.CallDecode() error = %v", err)
+			}
+			if resp.Price != "42.00" {
+				t.Errorf("Expected price 42.00, got: %q", resp.Price)
+			}
+		}
+		// The action expectation is used up, so the element expectation replies
+		_, err = client.Call(context.Background(), "urn:GetQuote", reqEnv)
+		var soapErr *soap.Error
+		if !errors.As(err, &soapErr) || soapErr.Fault == nil || soapErr.Fault.FaultString != "unknown symbol" {
+			t.Errorf("Expected the configured fault for SOAP %v, got: %v", version, err)
+		}
+	}
+	requests := server.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 recorded requests, got %d", len(requests))
+	}
+	var recorded quoteRequest
+	if err := requests[0].Envelope.DecodeBody(&recorded); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if requests[0].Action != "urn:GetQuote" || recorded.Symbol != "WAY" {
+		t.Errorf("Unexpected recorded request: %+v", requests[0])
+	}
+	if requests[2].Action != "urn:GetQuote" {
+		t.Errorf("Expected the SOAP 1.2 action to be recorded, got: %q", requests[2].Action)
+	}
+}
+
+func TestServer_Expectations(t *testing.T) {
+	t.Parallel()
+	rt := &recordingT{TB: t}
+	server := NewServer(rt)
+	server.ExpectAction("urn:Never")
+	server.ExpectAction("urn:Twice").Times(2)
+	client, err := soap.NewClient(soap.WithEndpoint(server.URL), soap.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := soap.NewEnvelope(soap.WithBody(&quoteRequest{Symbol: "WAY"}))
+	if _, err := client.Call(context.Background(), "urn:Twice", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if _, err := client.Call(context.Background(), "urn:Other", reqEnv); err == nil {
+		t.Error("Expected a fault for an unexpected request")
+	}
+	rt.finish()
+	if len(rt.errors) != 3 {
+		t.Fatalf("Expected 3 failures, got: %q", rt.errors)
+	}
+}
@@ -0,0 +1,131 @@
+package soaptest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// EqualXML reports whether two XML documents are equivalent.
+//
+// The comparison is namespace-aware: elements and attributes are compared by
+// namespace and local name, so documents using different prefixes for the
+// same namespaces are equal. Namespace declarations, attribute order,
+// comments, processing instructions and whitespace around text are ignored.
+func EqualXML(a, b []byte) (bool, error) {
+	ca, err := canonicalXML(a)
+	if err != nil {
+		return false, err
+	}
+	cb, err := canonicalXML(b)
+	if err != nil {
+		return false, err
+	}
+	return ca == cb, nil
+}
+
+// AssertXMLEqual fails the test if the XML documents are not equivalent, as
+// by [EqualXML]. The failure message shows the first differing line of the
+// normalized documents.
+func AssertXMLEqual(t testing.TB, expected, actual []byte) {
+	t.Helper()
+	ce, err := canonicalXML(expected)
+	if err != nil {
+		t.Fatalf("soaptest: invalid expected XML: %v", err)
+	}
+	ca, err := canonicalXML(actual)
+	if err != nil {
+		t.Fatalf("soaptest: invalid actual XML: %v\n%s", err, actual)
+	}
+	if ce != ca {
+		t.Errorf("soaptest: XML mismatch\n%s", describeDifference(ce, ca))
+	}
+}
+
+// AssertGoldenXML compares actual with the XML in the golden file, as by
+// [AssertXMLEqual]. When the SOAPTEST_UPDATE environment variable is set to
+// 1, the golden file is written with actual instead.
+func AssertGoldenXML(t testing.TB, goldenFile string, actual []byte) {
+	t.Helper()
+	if os.Getenv("SOAPTEST_UPDATE") == "1" {
+		if err := os.WriteFile(goldenFile, actual, 0o644); err != nil {
+			t.Fatalf("soaptest: failed to update golden file: %v", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("soaptest: failed to read golden file: %v", err)
+	}
+	AssertXMLEqual(t, expected, actual)
+}
+
+// canonicalXML renders an XML document in a normalized form, one element,
+// attribute or text node per line. The content of ignored elements is omitted.
+func canonicalXML(data []byte, ignored ...xml.Name) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var b strings.Builder
+	depth := 0
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse XML: %w", err)
+		}
+		indent := strings.Repeat("  ", depth)
+		switch t := token.(type) {
+		case xml.StartElement:
+			fmt.Fprintf(&b, "%s<{%s}%s>\n", indent, t.Name.Space, t.Name.Local)
+			var attrs []string
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				attrs = append(attrs, fmt.Sprintf("%s  @{%s}%s=%q\n", indent, attr.Name.Space, attr.Name.Local, attr.Value))
+			}
+			slices.Sort(attrs)
+			b.WriteString(strings.Join(attrs, ""))
+			if slices.Contains(ignored, t.Name) {
+				fmt.Fprintf(&b, "%s  (ignored)\n", indent)
+				if err := d.Skip(); err != nil {
+					return "", fmt.Errorf("failed to parse XML: %w", err)
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" {
+				fmt.Fprintf(&b, "%s%q\n", indent, text)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// describeDifference describes the first differing line of two canonical documents.
+func describeDifference(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a {
+			return fmt.Sprintf("line %d:\n  expected: %s\n  actual:   %s", i+1, strings.TrimSpace(e), strings.TrimSpace(a))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package soaptest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEqualXML(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{
+			name:     "different prefixes",
+			a:        `<s:Envelope xmlns:s="urn:env"><s:Body><m:Get xmlns:m="urn:m" id="1"/></s:Body></s:Envelope>`,
+			b:        `<env:Envelope xmlns:env="urn:env"><env:Body><Get xmlns="urn:m" id="1"></Get></env:Body></env:Envelope>`,
+			expected: true,
+		},
+		{
+			name:     "whitespace and attribute order",
+			a:        "<a x=\"1\" y=\"2\">\n  <b> text </b>\n</a>",
+			b:        `<a y="2" x="1"><b>text</b></a>`,
+			expected: true,
+		},
+		{
+			name:     "different namespace",
+			a:        `<a xmlns="urn:one"/>`,
+			b:        `<a xmlns="urn:two"/>`,
+			expected: false,
+		},
+		{
+			name:     "different text",
+			a:        `<a>1</a>`,
+			b:        `<a>2</a>`,
+			expected: false,
+		},
+		{
+			name:     "different child order",
+			a:        `<a><b/><c/></a>`,
+			b:        `<a><c/><b/></a>`,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			equal, err := EqualXML([]byte(tt.a), []byte(tt.b))
+			if err != nil {
+				t.Fatalf("EqualXML() error = %v", err)
+			}
+			if equal != tt.expected {
+				t.Errorf("EqualXML() = %v, expected %v", equal, tt.expected)
+			}
+		})
+	}
+	if _, err := EqualXML([]byte(`<a>`), []byte(`<a/>`)); err == nil {
+		t.Error("Expected an error for malformed XML")
+	}
+}
+
+func TestAssertGoldenXML(t *testing.T) {
+	t.Parallel()
+	goldenFile := filepath.Join(t.TempDir(), "envelope.xml")
+	if err := os.WriteFile(goldenFile, []byte(`<a xmlns="urn:a"><b>1</b></a>`), 0o644); err != nil {
+		t.Fatalf("Failed to write golden file: %v", err)
+	}
+	AssertGoldenXML(t, goldenFile, []byte(`<p:a xmlns:p="urn:a"> <p:b>1</p:b> </p:a>`))
+	rt := &recordingT{TB: t}
+	AssertGoldenXML(rt, goldenFile, []byte(`<a xmlns="urn:a"><b>2</b></a>`))
+	if len(rt.errors) != 1 {
+		t.Errorf("Expected a mismatch to be reported, got: %q", rt.errors)
+	}
+}
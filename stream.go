@@ -0,0 +1,206 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CallDecode executes a SOAP request like [Client.Call], but decodes the first
+// element of the response body into v while the HTTP response is read, using
+// [xml.Unmarshal] semantics. The response is never buffered in full, which
+// keeps memory usage flat for large responses. A nil v discards the body.
+//
+// The returned envelope carries the response header, but no body content.
+// Responses that must be inspected as a whole are read into memory as by
+// [Client.Call] before v is decoded: multipart/related responses, responses
+// whose signature is verified, HTTP error responses, and all responses of
+// clients with [WithMiddleware].
+func (c *Client) CallDecode(
+	ctx context.Context,
+	action string,
+	requestEnvelope *Envelope,
+	v any,
+	opts ...ClientOption,
+) (env *Envelope, err error) {
+	config := c.config.withCall(opts...)
+	ctx, tracer := startCallTrace(ctx, action, config)
+	defer func() { tracer.done(ctx, err) }()
+	defer func() {
+		if err = config.decodeResponseHeaders(env, err); err != nil {
+			env = nil
+		}
+	}()
+	if len(config.middleware) > 0 {
+		responseEnvelope, err := config.invoke(ctx, action, requestEnvelope, c.invoker(config))
+		if err == nil && v != nil {
+			err = responseEnvelope.DecodeBody(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return responseEnvelope, nil
+	}
+	resp, messageID, err := c.send(ctx, action, requestEnvelope, config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var responseEnvelope *Envelope
+	if canStreamResponse(resp, config) {
+		responseEnvelope, err = streamResponse(resp, config, v)
+	} else {
+		responseEnvelope, err = readResponse(resp, config)
+		if err == nil && v != nil {
+			err = responseEnvelope.DecodeBody(v)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config.addressing != nil {
+		if err := checkRelatesTo(responseEnvelope, messageID, config.addressing); err != nil {
+			return nil, err
+		}
+	}
+	return responseEnvelope, nil
+}
+
+// canStreamResponse reports whether the response envelope can be decoded
+// while the response body is read.
+func canStreamResponse(resp *http.Response, config clientConfig) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300 &&
+		!isMultipartRelated(resp.Header.Get("Content-Type")) &&
+		config.security.verificationRoots == nil
+}
+
+// streamResponse decodes the response envelope from the HTTP response body,
+// decoding the first body element into v. SOAP faults are returned as [*Error].
+func streamResponse(resp *http.Response, config clientConfig, v any) (*Envelope, error) {
+	d := xml.NewDecoder(limitResponseBody(resp.Body, config.maxResponseBytes))
+	start, ok, err := nextElement(d)
+	if err == nil && (!ok || start.Name.Local != "Envelope") {
+		err = fmt.Errorf("expected a SOAP envelope")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SOAP response: %w", err)
+	}
+	env := &Envelope{XMLName: start.Name}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "encodingStyle" {
+			env.EncodingStyle = attr.Value
+			continue
+		}
+		env.Attrs = append(env.Attrs, attr)
+	}
+	for {
+		child, ok, err := nextElement(d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal SOAP response: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("failed to unmarshal SOAP response: missing SOAP body")
+		}
+		switch child.Name.Local {
+		case "Header":
+			var header Header
+			if err := d.DecodeElement(&header, &child); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal SOAP header: %w", err)
+			}
+			env.Header = &header
+		case "Body":
+			env.Body = Body{XMLName: child.Name, Attrs: child.Attr}
+			return env, streamBody(d, resp.StatusCode, env, v)
+		default:
+			if err := d.Skip(); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal SOAP response: %w", err)
+			}
+		}
+	}
+}
+
+// streamBody decodes the first element of the SOAP body into v.
+func streamBody(d *xml.Decoder, statusCode int, env *Envelope, v any) error {
+	start, ok, err := nextElement(d)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	if !ok {
+		if v == nil {
+			return nil
+		}
+		return fmt.Errorf("failed to unmarshal response body: empty SOAP body")
+	}
+	if start.Name.Local == "Fault" && (start.Name.Space == Namespace || start.Name.Space == Namespace12) {
+		var fault Fault
+		if err := d.DecodeElement(&fault, &start); err != nil {
+			return fmt.Errorf("failed to unmarshal SOAP fault: %w", err)
+		}
+		fault.inheritNamespaces(env)
+		return &Error{StatusCode: statusCode, Envelope: env, Fault: &fault}
+	}
+	if v == nil {
+		return nil
+	}
+	if err := d.DecodeElement(v, &start); err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return nil
+}
+
+// nextElement returns the next child element of the current element. It
+// reports false when the current element ends first.
+func nextElement(d *xml.Decoder) (xml.StartElement, bool, error) {
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, false, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return xml.StartElement{}, false, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t, true, nil
+		case xml.EndElement:
+			return xml.StartElement{}, false, nil
+		}
+	}
+}
+
+// limitResponseBody returns a reader that fails with [*ResponseTooLargeError]
+// once more than limit bytes are read. A limit of zero or less means no limit.
+func limitResponseBody(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, limit: limit, remaining: limit}
+}
+
+// limitedReader is a reader that fails when its limit is exceeded.
+type limitedReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+// Read implements [io.Reader].
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for more data to tell an exact fit from an overflow
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, &ResponseTooLargeError{Limit: l.limit}
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,129 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// inventoryResponse is the decoded body of the streamed test responses.
+type inventoryResponse struct {
+	XMLName xml.Name `xml:"http://example.com/inventory ExportResponse"`
+	Items   []string `xml:"http://example.com/inventory Item"`
+}
+
+// staticServer returns a test server responding with the given status and body.
+func staticServer(t *testing.T, statusCode int, body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestClient_CallDecode(t *testing.T) {
+	t.Parallel()
+	// The body prefix is declared on the Envelope, as many servers do
+	server := staticServer(t, http.StatusOK, `<?xml version="1.0"?>
+		<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" xmlns:inv="http://example.com/inventory">
+			<s:Header><inv:Page>1</inv:Page></s:Header>
+			<s:Body>
+				<inv:ExportResponse><inv:Item>a</inv:Item><inv:Item>b</inv:Item></inv:ExportResponse>
+			</s:Body>
+		</s:Envelope>`)
+	defer server.Close()
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+	var result inventoryResponse
+	respEnv, err := client.CallDecode(context.Background(), "", reqEnv, &result)
+	if err != nil {
+		t.Fatalf("Client.CallDecode() error = %v", err)
+	}
+	if strings.Join(result.Items, ",") != "a,b" {
+		t.Errorf("Unexpected items: %v", result.Items)
+	}
+	var page struct {
+		Value string `xml:",chardata"`
+	}
+	if err := respEnv.DecodeHeader(xml.Name{Space: "http://example.com/inventory", Local: "Page"}, &page); err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+	if page.Value != "1" {
+		t.Errorf("Expected page header '1', got: %q", page.Value)
+	}
+	if len(respEnv.Body.Content) != 0 {
+		t.Errorf("Expected no buffered body content, got: %s", respEnv.Body.Content)
+	}
+}
+
+func TestClient_CallDecodeFault(t *testing.T) {
+	t.Parallel()
+	fault := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+		<soapenv:Body>
+			<soapenv:Fault>
+				<faultcode>soapenv:Server</faultcode>
+				<faultstring>Export failed</faultstring>
+			</soapenv:Fault>
+		</soapenv:Body>
+	</soapenv:Envelope>`
+	for _, statusCode := range []int{http.StatusOK, http.StatusInternalServerError} {
+		server := staticServer(t, statusCode, fault)
+		client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+		var result inventoryResponse
+		_, err = client.CallDecode(context.Background(), "", reqEnv, &result)
+		server.Close()
+		var soapErr *Error
+		if !errors.As(err, &soapErr) {
+			t.Fatalf("Expected *Error for HTTP %d, got: %v", statusCode, err)
+		}
+		if soapErr.StatusCode != statusCode || soapErr.Fault == nil || soapErr.Fault.FaultString != "Export failed" {
+			t.Errorf("Unexpected error for HTTP %d: %+v", statusCode, soapErr)
+		}
+	}
+}
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	t.Parallel()
+	response := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+		`<ExportResponse xmlns="http://example.com/inventory">` +
+		strings.Repeat("<Item>item</Item>", 1000) +
+		`</ExportResponse></soapenv:Body></soapenv:Envelope>`
+	server := staticServer(t, http.StatusOK, response)
+	defer server.Close()
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+	client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithMaxResponseBytes(1024))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	var tooLarge *ResponseTooLargeError
+	var result inventoryResponse
+	if _, err := client.CallDecode(context.Background(), "", reqEnv, &result); !errors.As(err, &tooLarge) {
+		t.Errorf("Expected *ResponseTooLargeError from CallDecode, got: %v", err)
+	} else if tooLarge.Limit != 1024 {
+		t.Errorf("Expected limit 1024, got: %d", tooLarge.Limit)
+	}
+	if _, err := client.Call(context.Background(), "", reqEnv); !errors.As(err, &tooLarge) {
+		t.Errorf("Expected *ResponseTooLargeError from Call, got: %v", err)
+	}
+	// A limit matching the exact response size is not exceeded
+	var complete inventoryResponse
+	_, err = client.CallDecode(context.Background(), "", reqEnv, &complete, WithMaxResponseBytes(int64(len(response))))
+	if err != nil {
+		t.Fatalf("Client.CallDecode() error = %v", err)
+	}
+	if len(complete.Items) != 1000 {
+		t.Errorf("Expected 1000 items, got %d", len(complete.Items))
+	}
+}
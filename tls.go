@@ -0,0 +1,266 @@
+package soap
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ErrCertificateNotPinned is returned when no certificate of a server matches
+// the certificates pinned with [WithPinnedCertificates].
+var ErrCertificateNotPinned = errors.New("soap: server certificate is not pinned")
+
+// tlsOptions is the TLS configuration of the connections of a client.
+type tlsOptions struct {
+	certificates []tls.Certificate
+	rootCAs      *x509.CertPool
+	pins         [][sha256.Size]byte
+	minVersion   uint16
+	transports   *tlsTransportCache
+}
+
+// tlsTransportCache holds the transports derived from base transports with
+// the same TLS options, so that calls with a base transport share its
+// connections.
+type tlsTransportCache struct {
+	mu         sync.Mutex
+	transports map[http.RoundTripper]http.RoundTripper
+	bases      []http.RoundTripper
+}
+
+// maxTLSTransports is the number of derived transports cached per TLS
+// options. The oldest is evicted when full, for callers creating a base
+// transport for every call.
+const maxTLSTransports = 16
+
+// errTLSCallOption is the error of TLS options passed to a call.
+var errTLSCallOption = errors.New("TLS options apply to clients: pass them to NewClient")
+
+// updateTLS changes a copy of the TLS options, so that options applied to a
+// configuration do not change those it was copied from.
+func (c *clientConfig) updateTLS(update func(*tlsOptions)) {
+	var options tlsOptions
+	if c.tls != nil {
+		options = *c.tls
+		options.certificates = slices.Clip(options.certificates)
+		options.pins = slices.Clip(options.pins)
+	}
+	update(&options)
+	options.transports = &tlsTransportCache{}
+	c.tls = &options
+}
+
+// WithClientCertificate presents a certificate to servers requesting client
+// authentication (mutual TLS).
+//
+// TLS options configure a copy of the transport of [WithHTTPClient], which
+// must be an [*http.Transport], or a [DebugTransport] with one as Next. The
+// default is a copy of [http.DefaultTransport]. TLS options apply to clients:
+// calls passed them fail.
+func WithClientCertificate(certificate tls.Certificate) ClientOption {
+	return func(c *clientConfig) {
+		c.updateTLS(func(o *tlsOptions) {
+			o.certificates = append(o.certificates, certificate)
+		})
+	}
+}
+
+// WithClientCertificateFiles presents the certificate of a pair of PEM files,
+// like [WithClientCertificate]. The certificate file may hold the chain of
+// the certificate after it.
+func WithClientCertificateFiles(certFile, keyFile string) ClientOption {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return withError(fmt.Errorf("failed to load client certificate: %w", err))
+	}
+	return WithClientCertificate(certificate)
+}
+
+// WithPKCS12File presents the certificate of a PKCS#12 bundle, such as a .p12
+// or .pfx file, like [WithClientCertificate]. The bundle must hold a private
+// key and its certificate, and may hold the certificates of its chain.
+//
+// Bundles encrypted with PBES2, the OpenSSL 3 default, and with the legacy
+// 3DES and RC2 schemes, such as those of OpenSSL 1 and Windows, are supported.
+func WithPKCS12File(file, password string) ClientOption {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return withError(fmt.Errorf("failed to read PKCS#12 file: %w", err))
+	}
+	certificate, err := parsePKCS12(data, password)
+	if err != nil {
+		return withError(fmt.Errorf("failed to load PKCS#12 file %s: %w", file, err))
+	}
+	return WithClientCertificate(certificate)
+}
+
+// parsePKCS12 parses a DER-encoded PKCS#12 bundle into a client certificate.
+func parsePKCS12(data []byte, password string) (tls.Certificate, error) {
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	certificate := tls.Certificate{PrivateKey: key, Leaf: leaf}
+	certificate.Certificate = append(certificate.Certificate, leaf.Raw)
+	for _, ca := range chain {
+		certificate.Certificate = append(certificate.Certificate, ca.Raw)
+	}
+	return certificate, nil
+}
+
+// WithRootCAs sets the certificate authorities trusted to verify servers,
+// instead of those of the system.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *clientConfig) {
+		c.updateTLS(func(o *tlsOptions) {
+			o.rootCAs = pool
+		})
+	}
+}
+
+// WithRootCAFile trusts the certificate authorities of a PEM file to verify
+// servers, instead of those of the system.
+func WithRootCAFile(file string) ClientOption {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return withError(fmt.Errorf("failed to read root CA file: %w", err))
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return withError(fmt.Errorf("no certificate found in root CA file %s", file))
+	}
+	return WithRootCAs(pool)
+}
+
+// WithPinnedCertificates only accepts servers presenting one of the pinned
+// certificates, the server certificate or one of its chain. Certificates are
+// pinned by their SHA-256 fingerprint in hex, as printed by
+// openssl x509 -fingerprint -sha256, with or without colons.
+//
+// Pinning applies in addition to the verification of the certificate chain.
+// Connections to other servers fail with [ErrCertificateNotPinned].
+func WithPinnedCertificates(fingerprints ...string) ClientOption {
+	pins := make([][sha256.Size]byte, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		var pin [sha256.Size]byte
+		decoded, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+		if err != nil || len(decoded) != len(pin) {
+			return withError(fmt.Errorf("invalid SHA-256 certificate fingerprint %q", fingerprint))
+		}
+		copy(pin[:], decoded)
+		pins = append(pins, pin)
+	}
+	return func(c *clientConfig) {
+		c.updateTLS(func(o *tlsOptions) {
+			o.pins = append(o.pins, pins...)
+		})
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version, such as
+// [tls.VersionTLS13]. Defaults to TLS 1.2.
+func WithMinTLSVersion(version uint16) ClientOption {
+	return func(c *clientConfig) {
+		c.updateTLS(func(o *tlsOptions) {
+			o.minVersion = version
+		})
+	}
+}
+
+// cachedTransport returns a copy of a base transport with the TLS options,
+// made once per base transport.
+func (o *tlsOptions) cachedTransport(base http.RoundTripper) (http.RoundTripper, error) {
+	switch base.(type) {
+	case nil, *http.Transport, *DebugTransport:
+	default:
+		return o.transport(base)
+	}
+	o.transports.mu.Lock()
+	defer o.transports.mu.Unlock()
+	if transport, ok := o.transports.transports[base]; ok {
+		return transport, nil
+	}
+	transport, err := o.transport(base)
+	if err != nil {
+		return nil, err
+	}
+	cache := o.transports
+	if cache.transports == nil {
+		cache.transports = make(map[http.RoundTripper]http.RoundTripper)
+	}
+	if len(cache.bases) >= maxTLSTransports {
+		oldest := cache.bases[0]
+		closeIdleConnections(cache.transports[oldest])
+		delete(cache.transports, oldest)
+		cache.bases = slices.Delete(cache.bases, 0, 1)
+	}
+	cache.transports[base] = transport
+	cache.bases = append(cache.bases, base)
+	return transport, nil
+}
+
+// closeIdleConnections closes the idle connections of a derived transport.
+func closeIdleConnections(transport http.RoundTripper) {
+	switch transport := transport.(type) {
+	case *http.Transport:
+		transport.CloseIdleConnections()
+	case *DebugTransport:
+		closeIdleConnections(transport.Next)
+	}
+}
+
+// transport returns a copy of a base transport with the TLS options.
+func (o *tlsOptions) transport(base http.RoundTripper) (http.RoundTripper, error) {
+	var transport *http.Transport
+	switch base := base.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	case *DebugTransport:
+		next, err := o.transport(base.Next)
+		if err != nil {
+			return nil, err
+		}
+		debug := *base
+		debug.Next = next
+		return &debug, nil
+	default:
+		return nil, fmt.Errorf("TLS options require an *http.Transport, got %T", base)
+	}
+	config := transport.TLSClientConfig
+	if config == nil {
+		config = &tls.Config{}
+	}
+	config = config.Clone()
+	config.Certificates = append(config.Certificates, o.certificates...)
+	if o.rootCAs != nil {
+		config.RootCAs = o.rootCAs
+	}
+	if o.minVersion != 0 {
+		config.MinVersion = o.minVersion
+	}
+	if len(o.pins) > 0 {
+		pins := o.pins
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			for _, certificate := range state.PeerCertificates {
+				if slices.Contains(pins, sha256.Sum256(certificate.Raw)) {
+					return nil
+				}
+			}
+			return ErrCertificateNotPinned
+		}
+	}
+	transport.TLSClientConfig = config
+	return transport, nil
+}
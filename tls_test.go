@@ -0,0 +1,288 @@
+package soap
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// newMTLSServer starts a TLS test server requiring client certificates issued
+// by the test CA, with the maximum TLS version if not zero and the connection
+// state hook if not nil.
+func newMTLSServer(t *testing.T, maxVersion uint16, connState func(net.Conn, http.ConnState)) *httptest.Server {
+	t.Helper()
+	caPEM, err := os.ReadFile("testdata/ca.pem")
+	if err != nil {
+		t.Fatalf("Failed to read CA: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(caPEM)
+	server := httptest.NewUnstartedServer(newEchoHandler())
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MaxVersion: maxVersion,
+	}
+	server.Config.ConnState = connState
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_TLS(t *testing.T) {
+	t.Parallel()
+	server := newMTLSServer(t, 0, nil)
+	serverRoots := x509.NewCertPool()
+	serverRoots.AddCert(server.Certificate())
+	fingerprint := sha256.Sum256(server.Certificate().Raw)
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		wantErr string
+	}{
+		{
+			name: "PKCS#12 bundle",
+			opts: []ClientOption{WithPKCS12File("testdata/client.p12", "changeit"), WithRootCAs(serverRoots)},
+		},
+		{
+			name: "PEM files",
+			opts: []ClientOption{WithClientCertificateFiles("testdata/client.pem", "testdata/client.key"), WithRootCAs(serverRoots)},
+		},
+		{
+			name: "debug transport",
+			opts: []ClientOption{
+				WithHTTPClient(&http.Client{Transport: &DebugTransport{}}),
+				WithPKCS12File("testdata/client.p12", "changeit"),
+				WithRootCAs(serverRoots),
+			},
+		},
+		{
+			name: "pinned certificate",
+			opts: []ClientOption{
+				WithPKCS12File("testdata/client.p12", "changeit"),
+				WithRootCAs(serverRoots),
+				WithPinnedCertificates(strings.ToUpper(hex.EncodeToString(fingerprint[:]))),
+			},
+		},
+		{
+			name:    "no client certificate",
+			opts:    []ClientOption{WithRootCAs(serverRoots)},
+			wantErr: "certificate required",
+		},
+		{
+			name:    "untrusted server",
+			opts:    []ClientOption{WithPKCS12File("testdata/client.p12", "changeit"), WithRootCAFile("testdata/ca.pem")},
+			wantErr: "certificate signed by unknown authority",
+		},
+		{
+			name: "unpinned certificate",
+			opts: []ClientOption{
+				WithPKCS12File("testdata/client.p12", "changeit"),
+				WithRootCAs(serverRoots),
+				WithPinnedCertificates(strings.Repeat("ab:", 31) + "ab"),
+			},
+			wantErr: ErrCertificateNotPinned.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := NewClient(append([]ClientOption{WithEndpoint(server.URL), WithMaxRetries(0)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+			var resp echoResponse
+			_, err = client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Client.CallDecode() error = %v", err)
+			}
+			if resp.Message != "hello" {
+				t.Errorf("Expected echoed message, got: %q", resp.Message)
+			}
+		})
+	}
+
+	t.Run("call HTTP client", func(t *testing.T) {
+		t.Parallel()
+		var connections atomic.Int32
+		server := newMTLSServer(t, 0, func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				connections.Add(1)
+			}
+		})
+		serverRoots := x509.NewCertPool()
+		serverRoots.AddCert(server.Certificate())
+		client, err := NewClient(
+			WithEndpoint(server.URL),
+			WithPKCS12File("testdata/client.p12", "changeit"),
+			WithRootCAs(serverRoots),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		httpClient := &http.Client{Transport: &http.Transport{}}
+		for _, message := range []string{"hello", "world"} {
+			reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: message}))
+			var resp echoResponse
+			if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp, WithHTTPClient(httpClient)); err != nil {
+				t.Fatalf("Client.CallDecode() error = %v", err)
+			}
+		}
+		if got := connections.Load(); got != 1 {
+			t.Errorf("Expected 1 connection reused by the calls, got: %d", got)
+		}
+	})
+
+	t.Run("call TLS options", func(t *testing.T) {
+		t.Parallel()
+		client, err := NewClient(WithEndpoint(server.URL), WithRootCAs(serverRoots))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv, WithPKCS12File("testdata/client.p12", "changeit"))
+		if !errors.Is(err, errTLSCallOption) {
+			t.Errorf("Expected TLS options of a call to fail, got: %v", err)
+		}
+	})
+
+	t.Run("evicted transport", func(t *testing.T) {
+		t.Parallel()
+		closed := make(chan struct{}, 1)
+		server := newMTLSServer(t, 0, func(_ net.Conn, state http.ConnState) {
+			if state == http.StateClosed {
+				select {
+				case closed <- struct{}{}:
+				default:
+				}
+			}
+		})
+		serverRoots := x509.NewCertPool()
+		serverRoots.AddCert(server.Certificate())
+		client, err := NewClient(
+			WithEndpoint(server.URL),
+			WithPKCS12File("testdata/client.p12", "changeit"),
+			WithRootCAs(serverRoots),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		httpClient := &http.Client{Transport: &http.Transport{}}
+		if _, err := client.Call(context.Background(), "urn:echo", reqEnv, WithHTTPClient(httpClient)); err != nil {
+			t.Fatalf("Client.Call() error = %v", err)
+		}
+		// Filling the cache evicts the transport of the call, closing its
+		// idle connection
+		for range maxTLSTransports {
+			if _, err := client.config.tls.cachedTransport(&http.Transport{}); err != nil {
+				t.Fatalf("cachedTransport() error = %v", err)
+			}
+		}
+		if got := len(client.config.tls.transports.transports); got != maxTLSTransports {
+			t.Errorf("Expected %d cached transports, got: %d", maxTLSTransports, got)
+		}
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Error("Expected the idle connection of the evicted transport to be closed")
+		}
+	})
+
+	t.Run("minimum version", func(t *testing.T) {
+		t.Parallel()
+		server := newMTLSServer(t, tls.VersionTLS12, nil)
+		serverRoots := x509.NewCertPool()
+		serverRoots.AddCert(server.Certificate())
+		client, err := NewClient(
+			WithEndpoint(server.URL),
+			WithMaxRetries(0),
+			WithPKCS12File("testdata/client.p12", "changeit"),
+			WithRootCAs(serverRoots),
+			WithMinTLSVersion(tls.VersionTLS13),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		if _, err := client.Call(context.Background(), "urn:echo", reqEnv); err == nil || !strings.Contains(err.Error(), "protocol version") {
+			t.Errorf("Expected a protocol version error, got: %v", err)
+		}
+	})
+}
+
+func TestNewClient_TLSErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		wantErr string
+	}{
+		{
+			name:    "missing PKCS#12 file",
+			opts:    []ClientOption{WithPKCS12File("testdata/missing.p12", "changeit")},
+			wantErr: "failed to read PKCS#12 file",
+		},
+		{
+			name:    "wrong PKCS#12 password",
+			opts:    []ClientOption{WithPKCS12File("testdata/client.p12", "wrong")},
+			wantErr: pkcs12.ErrIncorrectPassword.Error(),
+		},
+		{
+			name:    "invalid fingerprint",
+			opts:    []ClientOption{WithPinnedCertificates("not-hex")},
+			wantErr: "invalid SHA-256 certificate fingerprint",
+		},
+		{
+			name: "custom transport",
+			opts: []ClientOption{
+				WithHTTPClient(&http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}),
+				WithMinTLSVersion(tls.VersionTLS13),
+			},
+			wantErr: "TLS options require an *http.Transport",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewClient(tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("call option", func(t *testing.T) {
+		t.Parallel()
+		client, err := NewClient(WithEndpoint("https://127.0.0.1:0"))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv, WithRootCAFile("testdata/missing.pem"))
+		var pathErr *os.PathError
+		if !errors.As(err, &pathErr) {
+			t.Errorf("Expected the file error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package soap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before its expiry a cached token is refreshed,
+// so that it does not expire in flight.
+const tokenExpiryDelta = 30 * time.Second
+
+// Token is an access token sent in the Authorization header of requests.
+type Token struct {
+	// AccessToken is the token.
+	AccessToken string
+	// TokenType is the authorization scheme of the token. Defaults to Bearer.
+	TokenType string
+	// Expiry is when the token expires. The zero value means it never does.
+	Expiry time.Time
+}
+
+// valid reports whether the token can be used, and is not about to expire.
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || now.Add(tokenExpiryDelta).Before(t.Expiry))
+}
+
+// TokenSource supplies the access tokens of a client. Sources are not
+// expected to cache tokens; [WithTokenSource] does.
+type TokenSource interface {
+	// Token returns a new token.
+	Token(ctx context.Context) (*Token, error)
+}
+
+// StaticToken returns a token source always returning the same bearer token.
+func StaticToken(accessToken string) TokenSource {
+	return staticTokenSource{token: &Token{AccessToken: accessToken}}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// ClientCredentials is a token source fetching tokens from an OAuth2 token
+// endpoint with the client credentials grant (RFC 6749, section 4.4).
+type ClientCredentials struct {
+	// TokenURL is the URL of the token endpoint.
+	TokenURL string
+	// ClientID and ClientSecret authenticate the client to the token
+	// endpoint, with HTTP Basic authentication.
+	ClientID     string
+	ClientSecret string
+	// Scopes are the optional scopes of the requested tokens.
+	Scopes []string
+	// EndpointParams are additional parameters of token requests, such as
+	// the audience or resource some token endpoints require.
+	EndpointParams url.Values
+	// HTTPClient is the client making token requests. Defaults to
+	// [http.DefaultClient].
+	HTTPClient *http.Client
+}
+
+// Token requests a new token from the token endpoint.
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	for name, values := range c.EndpointParams {
+		form[name] = values
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var tokenResp struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	jsonErr := json.Unmarshal(body, &tokenResp)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if jsonErr == nil && tokenResp.Error != "" {
+			if tokenResp.ErrorDescription != "" {
+				return nil, fmt.Errorf("token request failed: %s: %s", tokenResp.Error, tokenResp.ErrorDescription)
+			}
+			return nil, fmt.Errorf("token request failed: %s", tokenResp.Error)
+		}
+		return nil, fmt.Errorf("token request failed: HTTP %d", resp.StatusCode)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", jsonErr)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+	token := &Token{AccessToken: tokenResp.AccessToken, TokenType: tokenResp.TokenType}
+	if expiresIn, err := tokenResp.ExpiresIn.Int64(); err == nil && expiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// WithTokenSource authenticates requests with tokens of a source, such as
+// [ClientCredentials], in the Authorization header.
+//
+// Tokens are cached until shortly before they expire. When a request is
+// rejected with HTTP 401, or with a WS-Security authentication fault in an
+// HTTP error response, the token is refreshed and the request sent again,
+// once. The cache is shared by the calls of a client created with the option.
+func WithTokenSource(source TokenSource) ClientOption {
+	auth := &tokenAuth{source: source}
+	return func(c *clientConfig) {
+		c.auth = auth
+	}
+}
+
+// tokenAuth caches the tokens of a source.
+type tokenAuth struct {
+	source TokenSource
+	mu     sync.Mutex
+	token  *Token
+}
+
+// get returns the cached token, or a new one if it is about to expire.
+func (a *tokenAuth) get(ctx context.Context) (*Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token.valid(time.Now()) {
+		return a.token, nil
+	}
+	token, err := a.source.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	a.token = token
+	return token, nil
+}
+
+// invalidate drops a rejected token from the cache, unless it was already
+// replaced by a concurrent request.
+func (a *tokenAuth) invalidate(token *Token) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == token {
+		a.token = nil
+	}
+}
+
+func (a *tokenAuth) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	for i := 0; ; i++ {
+		token, err := a.get(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		leg, err := authLeg(req, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		tokenType := token.TokenType
+		if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+			tokenType = "Bearer"
+		}
+		leg.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+		resp, err := next.RoundTrip(leg)
+		if err != nil || i > 0 || !isTokenRejected(resp) {
+			return resp, err
+		}
+		discardResponse(resp)
+		a.invalidate(token)
+	}
+}
+
+// authFaultCodes are the fault codes of authentication failures, from WS-Security.
+var authFaultCodes = []string{
+	"FailedAuthentication",
+	"InvalidSecurity",
+	"InvalidSecurityToken",
+	"SecurityTokenUnavailable",
+}
+
+// isTokenRejected reports whether a response rejects the token of a request.
+func isTokenRejected(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	if resp.StatusCode < 400 {
+		return false
+	}
+	fault := peekFault(resp)
+	if fault == nil {
+		return false
+	}
+	codes := []string{fault.FaultCode}
+	if fault.Code != nil {
+		for code := fault.Code.Subcode; code != nil; code = code.Subcode {
+			codes = append(codes, code.Value)
+		}
+	}
+	for _, code := range codes {
+		for part := range strings.SplitSeq(localName(code), ".") {
+			if slices.Contains(authFaultCodes, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package soap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newTokenServer starts a test OAuth2 token endpoint issuing numbered tokens
+// with a lifetime in seconds. It counts the issued tokens.
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		id, secret, _ := r.BasicAuth()
+		if id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"unknown client"}`))
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "soap:read soap:write" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, issued.Add(1), expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+// newBearerServer starts a test SOAP server accepting the bearer tokens for
+// which accept returns true, and rejecting others with the rejection.
+func newBearerServer(t *testing.T, accept func(token string) bool, reject http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	echo := newEchoHandler()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !accept(token) {
+			reject(w, r)
+			return
+		}
+		echo.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_TokenSource(t *testing.T) {
+	t.Parallel()
+	unauthorized := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	invalidTokenFault := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+			`<soapenv:Fault xmlns:wsse="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd">` +
+			`<faultcode>wsse:InvalidSecurityToken</faultcode><faultstring>token revoked</faultstring>` +
+			`</soapenv:Fault></soapenv:Body></soapenv:Envelope>`))
+	}
+	tests := []struct {
+		name      string
+		expiresIn int
+		accept    func(token string) bool
+		reject    http.HandlerFunc
+		issued    int32
+	}{
+		{name: "cached", expiresIn: 3600, accept: func(token string) bool { return token == "token-1" }, issued: 1},
+		{name: "about to expire", expiresIn: 10, accept: func(token string) bool { return token != "" }, issued: 3},
+		{name: "refresh on 401", expiresIn: 3600, accept: func(token string) bool { return token == "token-2" }, reject: unauthorized, issued: 2},
+		{name: "refresh on fault", expiresIn: 3600, accept: func(token string) bool { return token == "token-2" }, reject: invalidTokenFault, issued: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tokenServer, issued := newTokenServer(t, tt.expiresIn)
+			server := newBearerServer(t, tt.accept, tt.reject)
+			client, err := NewClient(WithEndpoint(server.URL), WithTokenSource(&ClientCredentials{
+				TokenURL:     tokenServer.URL,
+				ClientID:     "client",
+				ClientSecret: "secret",
+				Scopes:       []string{"soap:read", "soap:write"},
+			}))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			for i := range 3 {
+				reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: fmt.Sprintf("hello %d", i)}))
+				var resp echoResponse
+				if _, err := client.CallDecode(context.Background(), "urn:echo", reqEnv, &resp); err != nil {
+					t.Fatalf("Client.CallDecode() error = %v", err)
+				}
+				if want := fmt.Sprintf("hello %d", i); resp.Message != want {
+					t.Errorf("Expected message %q, got: %q", want, resp.Message)
+				}
+			}
+			if got := issued.Load(); got != tt.issued {
+				t.Errorf("Expected %d tokens to be issued, got: %d", tt.issued, got)
+			}
+		})
+	}
+
+	t.Run("token endpoint error", func(t *testing.T) {
+		t.Parallel()
+		tokenServer, _ := newTokenServer(t, 3600)
+		client, err := NewClient(WithEndpoint("http://127.0.0.1:0"), WithTokenSource(&ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "client",
+			ClientSecret: "wrong",
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+		_, err = client.Call(context.Background(), "urn:echo", reqEnv, WithMaxRetries(0))
+		if err == nil || !strings.Contains(err.Error(), "invalid_client: unknown client") {
+			t.Errorf("Expected the token endpoint error, got: %v", err)
+		}
+	})
+}
+
+func TestClient_StaticToken(t *testing.T) {
+	t.Parallel()
+	server := newBearerServer(t, func(token string) bool { return token == "static" }, nil)
+	client, err := NewClient(WithEndpoint(server.URL), WithTokenSource(StaticToken("static")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+	if _, err := client.Call(context.Background(), "urn:echo", reqEnv); err != nil {
+		t.Errorf("Client.Call() error = %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ClientTrace is a set of hooks called during the lifecycle of a SOAP call,
+// made by [Client.Call] or [Client.CallDecode]. Any hook may be nil.
+//
+// Hooks are called synchronously from the goroutine making the call, and
+// receive the context of the call.
+type ClientTrace struct {
+	// RequestMarshalled is called when the request envelope has been encoded.
+	RequestMarshalled func(ctx context.Context, info RequestMarshalledInfo)
+
+	// AttemptStart is called before each HTTP attempt, including retries.
+	AttemptStart func(ctx context.Context, info AttemptStartInfo)
+
+	// RetryScheduled is called when a failed attempt will be retried.
+	RetryScheduled func(ctx context.Context, info RetryScheduledInfo)
+
+	// ResponseReceived is called when an attempt received an HTTP response.
+	ResponseReceived func(ctx context.Context, info ResponseReceivedInfo)
+
+	// FaultDecoded is called when the call failed with a SOAP fault.
+	FaultDecoded func(ctx context.Context, info FaultDecodedInfo)
+
+	// CallDone is called when the call finished, successfully or not.
+	CallDone func(ctx context.Context, info CallDoneInfo)
+}
+
+// RequestMarshalledInfo describes an encoded request.
+type RequestMarshalledInfo struct {
+	Action   string
+	Endpoint string
+	// Size is the size of the HTTP request body in bytes.
+	Size int
+}
+
+// AttemptStartInfo describes an HTTP attempt.
+type AttemptStartInfo struct {
+	Action   string
+	Endpoint string
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+}
+
+// RetryScheduledInfo describes a scheduled retry.
+type RetryScheduledInfo struct {
+	Action   string
+	Endpoint string
+	// Attempt is the number of the failed attempt.
+	Attempt int
+	// Delay is the time to wait before the next attempt.
+	Delay time.Duration
+	// StatusCode is the HTTP status code of the failed attempt, or 0 if it
+	// failed without a response.
+	StatusCode int
+	// Err is the error of the failed attempt, if it failed without a response.
+	Err error
+}
+
+// ResponseReceivedInfo describes an HTTP response.
+type ResponseReceivedInfo struct {
+	Action      string
+	Endpoint    string
+	Attempt     int
+	StatusCode  int
+	ContentType string
+}
+
+// FaultDecodedInfo describes a SOAP fault response.
+type FaultDecodedInfo struct {
+	Action     string
+	Endpoint   string
+	StatusCode int
+	Fault      *Fault
+}
+
+// CallDoneInfo describes a finished call.
+type CallDoneInfo struct {
+	Action   string
+	Endpoint string
+	// Attempts is the number of HTTP attempts made.
+	Attempts int
+	// StatusCode is the HTTP status code of the last response, or 0 if none
+	// was received.
+	StatusCode int
+	// Duration is the time taken by the call, including retries.
+	Duration time.Duration
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// WithTrace sets the hooks called during the lifecycle of calls.
+func WithTrace(trace *ClientTrace) ClientOption {
+	return func(c *clientConfig) {
+		c.trace = trace
+	}
+}
+
+// callTracer tracks the state of a traced call. A nil *callTracer traces nothing.
+type callTracer struct {
+	trace      *ClientTrace
+	action     string
+	endpoint   string
+	start      time.Time
+	attempts   int
+	statusCode int
+}
+
+// callTracerKey is the context key of the callTracer of a call.
+type callTracerKey struct{}
+
+// startCallTrace returns a context carrying the tracer of a call, if the
+// configuration has a trace.
+func startCallTrace(ctx context.Context, action string, config clientConfig) (context.Context, *callTracer) {
+	if config.trace == nil {
+		return ctx, nil
+	}
+	tracer := &callTracer{trace: config.trace, action: action, endpoint: config.endpoint, start: time.Now()}
+	return context.WithValue(ctx, callTracerKey{}, tracer), tracer
+}
+
+// callTracerFrom returns the tracer of the call in the context, if any.
+func callTracerFrom(ctx context.Context) *callTracer {
+	tracer, _ := ctx.Value(callTracerKey{}).(*callTracer)
+	return tracer
+}
+
+func (t *callTracer) requestMarshalled(ctx context.Context, size int) {
+	if t == nil || t.trace.RequestMarshalled == nil {
+		return
+	}
+	t.trace.RequestMarshalled(ctx, RequestMarshalledInfo{Action: t.action, Endpoint: t.endpoint, Size: size})
+}
+
+func (t *callTracer) attemptStart(ctx context.Context, endpoint string) {
+	if t == nil {
+		return
+	}
+	t.attempts++
+	t.endpoint = endpoint
+	if t.trace.AttemptStart != nil {
+		t.trace.AttemptStart(ctx, AttemptStartInfo{Action: t.action, Endpoint: t.endpoint, Attempt: t.attempts})
+	}
+}
+
+func (t *callTracer) responseReceived(ctx context.Context, resp *http.Response) {
+	if t == nil {
+		return
+	}
+	t.statusCode = resp.StatusCode
+	if t.trace.ResponseReceived != nil {
+		t.trace.ResponseReceived(ctx, ResponseReceivedInfo{
+			Action:      t.action,
+			Endpoint:    t.endpoint,
+			Attempt:     t.attempts,
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		})
+	}
+}
+
+func (t *callTracer) retryScheduled(ctx context.Context, delay time.Duration, resp *http.Response, err error) {
+	if t == nil || t.trace.RetryScheduled == nil {
+		return
+	}
+	info := RetryScheduledInfo{Action: t.action, Endpoint: t.endpoint, Attempt: t.attempts, Delay: delay, Err: err}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	t.trace.RetryScheduled(ctx, info)
+}
+
+// done reports the fault of a failed call, if any, and the end of the call.
+func (t *callTracer) done(ctx context.Context, err error) {
+	if t == nil {
+		return
+	}
+	var soapErr *Error
+	if errors.As(err, &soapErr) && soapErr.Fault != nil && t.trace.FaultDecoded != nil {
+		t.trace.FaultDecoded(ctx, FaultDecodedInfo{
+			Action:     t.action,
+			Endpoint:   t.endpoint,
+			StatusCode: soapErr.StatusCode,
+			Fault:      soapErr.Fault,
+		})
+	}
+	if t.trace.CallDone != nil {
+		t.trace.CallDone(ctx, CallDoneInfo{
+			Action:     t.action,
+			Endpoint:   t.endpoint,
+			Attempts:   t.attempts,
+			StatusCode: t.statusCode,
+			Duration:   time.Since(t.start),
+			Err:        err,
+		})
+	}
+}
+
+// traceTransport reports the HTTP attempts of a traced call.
+type traceTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tracer := callTracerFrom(req.Context())
+	tracer.attemptStart(req.Context(), req.URL.String())
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		tracer.responseReceived(req.Context(), resp)
+	}
+	return resp, err
+}
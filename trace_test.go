@@ -0,0 +1,124 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newFlakyFaultServer starts a server that rate limits the first request and
+// answers the following ones with a SOAP fault.
+func newFlakyFaultServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+			`<soapenv:Fault><faultcode>soapenv:Server</faultcode><faultstring>unavailable</faultstring></soapenv:Fault>` +
+			`</soapenv:Body></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Trace(t *testing.T) {
+	t.Parallel()
+	server := newFlakyFaultServer(t)
+	var events []string
+	trace := &ClientTrace{
+		RequestMarshalled: func(ctx context.Context, info RequestMarshalledInfo) {
+			events = append(events, fmt.Sprintf("marshalled %s", info.Action))
+		},
+		AttemptStart: func(ctx context.Context, info AttemptStartInfo) {
+			events = append(events, fmt.Sprintf("attempt %d", info.Attempt))
+		},
+		ResponseReceived: func(ctx context.Context, info ResponseReceivedInfo) {
+			events = append(events, fmt.Sprintf("response %d", info.StatusCode))
+		},
+		RetryScheduled: func(ctx context.Context, info RetryScheduledInfo) {
+			events = append(events, fmt.Sprintf("retry %d after %d", info.Attempt, info.StatusCode))
+		},
+		FaultDecoded: func(ctx context.Context, info FaultDecodedInfo) {
+			events = append(events, fmt.Sprintf("fault %s", info.Fault.FaultCode))
+		},
+		CallDone: func(ctx context.Context, info CallDoneInfo) {
+			events = append(events, fmt.Sprintf("done attempts=%d status=%d failed=%t", info.Attempts, info.StatusCode, info.Err != nil))
+		},
+	}
+	client, err := NewClient(WithEndpoint(server.URL), WithTrace(trace))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+	if _, err := client.Call(context.Background(), "urn:echo", reqEnv); err == nil {
+		t.Fatal("Expected a fault error")
+	}
+	expected := []string{
+		"marshalled urn:echo",
+		"attempt 1",
+		"response 429",
+		"retry 1 after 429",
+		"attempt 2",
+		"response 500",
+		"fault soapenv:Server",
+		"done attempts=2 status=500 failed=true",
+	}
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected trace events:\n%s\nexpected:\n%s", strings.Join(events, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestNewSlogTrace(t *testing.T) {
+	t.Parallel()
+	server := newFlakyFaultServer(t)
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	client, err := NewClient(WithEndpoint(server.URL), WithTrace(NewSlogTrace(handler)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody(&echoRequest{Message: "hello"}))
+	_, _ = client.Call(context.Background(), "urn:echo", reqEnv)
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected a retry and a call record, got: %s", buf.String())
+	}
+	if records[0]["msg"] != "SOAP retry scheduled" || records[0]["http.status_code"] != float64(429) {
+		t.Errorf("Unexpected retry record: %v", records[0])
+	}
+	call := records[1]
+	if call["msg"] != "SOAP call failed" || call["level"] != "ERROR" {
+		t.Errorf("Unexpected call record: %v", call)
+	}
+	for key, value := range map[string]any{
+		"soap.action":      "urn:echo",
+		"soap.endpoint":    server.URL,
+		"soap.attempts":    float64(2),
+		"http.status_code": float64(500),
+		"soap.fault_code":  "soapenv:Server",
+	} {
+		if call[key] != value {
+			t.Errorf("Expected %s=%v, got: %v", key, value, call[key])
+		}
+	}
+}
@@ -141,6 +141,8 @@ type HTTPOperation struct {
 type BindingBody struct {
 	SOAP11Body           *SOAPBody             `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
 	SOAP12Body           *SOAPBody             `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ body"`
+	SOAP11Headers        []*SOAPHeader         `xml:"http://schemas.xmlsoap.org/wsdl/soap/ header"`
+	SOAP12Headers        []*SOAPHeader         `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ header"`
 	URLReplacement       *URLReplacement       `xml:"http://schemas.xmlsoap.org/wsdl/http/ urlReplacement"`
 	URLEncoded           *URLEncoded           `xml:"http://schemas.xmlsoap.org/wsdl/http/ urlEncoded"`
 	MIMEContent          []*MIMEContent        `xml:"http://schemas.xmlsoap.org/wsdl/mime/ content"`
@@ -156,6 +158,15 @@ type SOAPBody struct {
 	Parts         string `xml:"parts,attr"`
 }
 
+// SOAPHeader corresponds to the <soap:header> or <soap12:header> element.
+type SOAPHeader struct {
+	Message       string `xml:"message,attr"`
+	Part          string `xml:"part,attr"`
+	Use           string `xml:"use,attr"`
+	Namespace     string `xml:"namespace,attr"`
+	EncodingStyle string `xml:"encodingStyle,attr"`
+}
+
 // URLReplacement corresponds to the <http:urlReplacement> element.
 type URLReplacement struct{}
 
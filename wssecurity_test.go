@@ -0,0 +1,246 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPasswordDigest(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		nonce    []byte
+		created  string
+		password string
+		want     string
+	}{
+		{
+			name:     "sequential nonce",
+			nonce:    []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			created:  "2024-01-15T10:30:00.000Z",
+			password: "secret",
+			want:     "lod1ETywTSvYRr1XOMWtdI0Y1W8=",
+		},
+		{
+			name: "base64 nonce",
+			nonce: []byte{
+				0x2c, 0xaa, 0x88, 0xe8, 0x6f, 0xc0, 0x8a, 0x42,
+				0x82, 0x42, 0xb3, 0x74, 0xce, 0xa6, 0x45, 0x96,
+			},
+			created:  "2010-09-16T07:50:45Z",
+			password: "userpassword",
+			want:     "tuOSpGlFlIXsozq4HFNeeGeFLEI=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := passwordDigest(tt.nonce, tt.created, tt.password); got != tt.want {
+				t.Errorf("passwordDigest() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// securityHeader is the decoded wsse:Security header of a request.
+type securityHeader struct {
+	MustUnderstand string `xml:"http://schemas.xmlsoap.org/soap/envelope/ mustUnderstand,attr"`
+	Timestamp      *struct {
+		ID      string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Id,attr"`
+		Created string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Created"`
+		Expires string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Expires"`
+	} `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Timestamp"`
+	UsernameToken *struct {
+		Username string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd Username"`
+		Password struct {
+			Type  string `xml:"Type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd Password"`
+		Nonce   string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd Nonce"`
+		Created string `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd Created"`
+	} `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd UsernameToken"`
+}
+
+// captureSecurityHeader calls a test server with the given options and returns
+// the decoded wsse:Security header of the request.
+func captureSecurityHeader(t *testing.T, reqEnv *Envelope, opts ...ClientOption) *securityHeader {
+	t.Helper()
+	var captured []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured, _ = io.ReadAll(r.Body)
+		respEnv, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+		respXML, _ := xml.Marshal(respEnv)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(respXML)
+	}))
+	defer server.Close()
+	client, err := NewClient(append([]ClientOption{WithEndpoint(server.URL), WithMaxRetries(0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.Call(context.Background(), "", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	var env struct {
+		Header struct {
+			Security *securityHeader `xml:"http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd Security"`
+		} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header"`
+	}
+	if err := xml.Unmarshal(captured, &env); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v\n%s", err, captured)
+	}
+	if env.Header.Security == nil {
+		t.Fatalf("Expected a wsse:Security header, got: %s", captured)
+	}
+	return env.Header.Security
+}
+
+// withSecurityClock makes security headers deterministic.
+func withSecurityClock(now time.Time, nonce []byte) ClientOption {
+	return func(c *clientConfig) {
+		c.security.now = func() time.Time { return now }
+		c.security.rand = bytes.NewReader(nonce)
+	}
+}
+
+func TestClient_UsernameTokenDigest(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	nonce := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	security := captureSecurityHeader(
+		t,
+		reqEnv,
+		WithUsernameToken("alice", "secret", PasswordDigest),
+		WithSecurityTimestamp(5*time.Minute),
+		withSecurityClock(now, nonce),
+	)
+	if security.MustUnderstand != "1" {
+		t.Errorf("Expected soapenv:mustUnderstand=\"1\", got: %q", security.MustUnderstand)
+	}
+	if security.Timestamp == nil {
+		t.Fatal("Expected a wsu:Timestamp")
+	}
+	if security.Timestamp.Created != "2024-01-15T10:30:00.000Z" ||
+		security.Timestamp.Expires != "2024-01-15T10:35:00.000Z" {
+		t.Errorf("Unexpected timestamp: %+v", security.Timestamp)
+	}
+	token := security.UsernameToken
+	if token == nil {
+		t.Fatal("Expected a wsse:UsernameToken")
+	}
+	if token.Username != "alice" {
+		t.Errorf("Expected username 'alice', got: %s", token.Username)
+	}
+	if token.Password.Type != PasswordDigest.URI() {
+		t.Errorf("Expected digest password type, got: %s", token.Password.Type)
+	}
+	if token.Password.Value != "lod1ETywTSvYRr1XOMWtdI0Y1W8=" {
+		t.Errorf("Unexpected password digest: %s", token.Password.Value)
+	}
+	if token.Nonce != "AAECAwQFBgcICQoLDA0ODw==" {
+		t.Errorf("Unexpected nonce: %s", token.Nonce)
+	}
+	if token.Created != "2024-01-15T10:30:00.000Z" {
+		t.Errorf("Unexpected created: %s", token.Created)
+	}
+	if reqEnv.Header != nil {
+		t.Error("Expected the caller's envelope to be left unmodified")
+	}
+}
+
+func TestClient_UsernameTokenText(t *testing.T) {
+	t.Parallel()
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	security := captureSecurityHeader(t, reqEnv, WithUsernameToken("bob", "p<ss&word", PasswordText))
+	if security.Timestamp != nil {
+		t.Error("Expected no wsu:Timestamp without WithSecurityTimestamp")
+	}
+	if security.UsernameToken == nil {
+		t.Fatal("Expected a wsse:UsernameToken")
+	}
+	if got := security.UsernameToken.Password; got.Type != PasswordText.URI() || got.Value != "p<ss&word" {
+		t.Errorf("Unexpected password: %+v", got)
+	}
+	if security.UsernameToken.Nonce == "" {
+		t.Error("Expected a nonce")
+	}
+}
+
+func TestClient_SecurityTimestampOnly(t *testing.T) {
+	t.Parallel()
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	reqEnv.Header = &Header{
+		XMLName: xml.Name{Local: "soapenv:Header"},
+		Entries: []HeaderEntry{{XMLName: xml.Name{Local: "Existing"}}},
+	}
+	security := captureSecurityHeader(t, reqEnv, WithSecurityTimestamp(time.Minute))
+	if security.UsernameToken != nil {
+		t.Error("Expected no wsse:UsernameToken")
+	}
+	if security.Timestamp == nil || security.Timestamp.ID == "" {
+		t.Fatalf("Expected a wsu:Timestamp with an Id, got: %+v", security.Timestamp)
+	}
+	if len(reqEnv.Header.Entries) != 1 {
+		t.Errorf("Expected the caller's header to be left unmodified, got %d entries", len(reqEnv.Header.Entries))
+	}
+	created, err := time.Parse(time.RFC3339, security.Timestamp.Created)
+	if err != nil {
+		t.Fatalf("Invalid created timestamp: %v", err)
+	}
+	expires, err := time.Parse(time.RFC3339, security.Timestamp.Expires)
+	if err != nil {
+		t.Fatalf("Invalid expires timestamp: %v", err)
+	}
+	if expires.Sub(created) != time.Minute {
+		t.Errorf("Expected a TTL of one minute, got: %v", expires.Sub(created))
+	}
+}
+
+func TestClient_UsernameTokenRetry(t *testing.T) {
+	t.Parallel()
+	var nonces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var env struct {
+			Security securityHeader `xml:"Header>Security"`
+		}
+		if err := xml.Unmarshal(body, &env); err != nil || env.Security.UsernameToken == nil {
+			t.Errorf("Expected a UsernameToken, got: %s", body)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		nonces = append(nonces, env.Security.UsernameToken.Nonce)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		if len(nonces) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body/></soapenv:Envelope>`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithUsernameToken("alice", "secret", PasswordDigest),
+		WithIdempotent(true),
+		WithBackoff(ConstantBackoff(time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request>Test</request>`)))
+	if _, err := client.Call(context.Background(), "urn:test", reqEnv); err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if len(nonces) != 3 {
+		t.Fatalf("Expected 3 attempts, got: %d", len(nonces))
+	}
+	if nonces[0] == nonces[1] || nonces[1] == nonces[2] || nonces[0] == nonces[2] {
+		t.Errorf("Expected a fresh nonce on every attempt, got: %v", nonces)
+	}
+}
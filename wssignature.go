@@ -0,0 +1,438 @@
+package soap
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+	"time"
+)
+
+// XML Signature namespaces and algorithm URIs.
+const (
+	// DSigNamespace is the XML Signature namespace (ds).
+	DSigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	excC14NAlgorithm   = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	rsaSHA256Algorithm = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	rsaSHA1Algorithm   = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	sha256Algorithm    = "http://www.w3.org/2001/04/xmlenc#sha256"
+	sha1Algorithm      = "http://www.w3.org/2000/09/xmldsig#sha1"
+
+	x509TokenProfile = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-x509-token-profile-1.0#X509v3"
+)
+
+// Identifiers of the signed parts of outgoing envelopes.
+const (
+	bodyID                = "id-Body"
+	timestampID           = "TS-1"
+	binarySecurityTokenID = "X509-1"
+)
+
+// defaultSignedTimestampTTL is the timestamp TTL used when signing without [WithSecurityTimestamp].
+const defaultSignedTimestampTTL = 5 * time.Minute
+
+// timestampClockSkew is the difference allowed between the clocks of the
+// client and the service when checking the timestamps of responses.
+const timestampClockSkew = 5 * time.Minute
+
+// SignatureError is returned when the XML signature of a response is missing
+// or cannot be verified.
+type SignatureError struct {
+	// Reason describes why verification failed.
+	Reason string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SignatureError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid response signature: %s: %v", e.Reason, e.Err)
+	}
+	return "invalid response signature: " + e.Reason
+}
+
+// Unwrap returns the underlying error.
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// signingConfig holds the key material used to sign outgoing envelopes.
+type signingConfig struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+// WithSigning signs every request with an X.509 certificate, as per the
+// WS-Security X.509 Token Profile 1.0.
+//
+// The certificate is sent as a wsse:BinarySecurityToken, and the Body and
+// wsu:Timestamp are signed using exclusive canonicalization, SHA-256 digests
+// and RSA-SHA256. A timestamp valid for 5 minutes is added unless configured
+// with [WithSecurityTimestamp]. The key must be an RSA key.
+func WithSigning(certificate *x509.Certificate, key crypto.Signer) ClientOption {
+	return func(c *clientConfig) {
+		c.security.signing = &signingConfig{certificate: certificate, key: key}
+	}
+}
+
+// WithSignatureVerification requires successful responses to carry a valid XML
+// signature in their wsse:Security header, covering at least the Body and
+// made with a certificate that chains to one of the roots. A wsu:Timestamp in
+// the header must be covered by the signature too, and be current: created
+// no later than now and expiring no earlier, with 5 minutes of clock skew.
+// Responses that fail verification are reported as a [*SignatureError].
+//
+// Fault responses are not verified, as services commonly do not sign them.
+func WithSignatureVerification(roots *x509.CertPool) ClientOption {
+	return func(c *clientConfig) {
+		c.security.verificationRoots = roots
+	}
+}
+
+// wsseBinarySecurityToken is the wsse:BinarySecurityToken element.
+type wsseBinarySecurityToken struct {
+	XMLName      xml.Name `xml:"wsse:BinarySecurityToken"`
+	ID           string   `xml:"wsu:Id,attr"`
+	ValueType    string   `xml:"ValueType,attr"`
+	EncodingType string   `xml:"EncodingType,attr"`
+	Value        string   `xml:",chardata"`
+}
+
+// dsSignedInfo is the ds:SignedInfo element.
+type dsSignedInfo struct {
+	XMLName                xml.Name      `xml:"ds:SignedInfo"`
+	XMLNS                  string        `xml:"xmlns:ds,attr"`
+	CanonicalizationMethod dsAlgorithm   `xml:"ds:CanonicalizationMethod"`
+	SignatureMethod        dsAlgorithm   `xml:"ds:SignatureMethod"`
+	References             []dsReference `xml:"ds:Reference"`
+}
+
+// dsAlgorithm is an element identifying an algorithm.
+type dsAlgorithm struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+// dsReference is the ds:Reference element.
+type dsReference struct {
+	URI          string        `xml:"URI,attr"`
+	Transforms   []dsAlgorithm `xml:"ds:Transforms>ds:Transform"`
+	DigestMethod dsAlgorithm   `xml:"ds:DigestMethod"`
+	DigestValue  string        `xml:"ds:DigestValue"`
+}
+
+// dsKeyInfo is the ds:KeyInfo element referencing the binary security token.
+type dsKeyInfo struct {
+	XMLName   xml.Name `xml:"ds:KeyInfo"`
+	Reference struct {
+		URI       string `xml:"URI,attr"`
+		ValueType string `xml:"ValueType,attr"`
+	} `xml:"wsse:SecurityTokenReference>wsse:Reference"`
+}
+
+// binarySecurityToken returns the token carrying the signing certificate.
+func (c *signingConfig) binarySecurityToken() *wsseBinarySecurityToken {
+	return &wsseBinarySecurityToken{
+		ID:           binarySecurityTokenID,
+		ValueType:    x509TokenProfile,
+		EncodingType: messageSecurity + "#Base64Binary",
+		Value:        base64.StdEncoding.EncodeToString(c.certificate.Raw),
+	}
+}
+
+// sign computes the ds:Signature over the elements with the given identifiers
+// in the serialized envelope.
+func (c *signingConfig) sign(envelopeXML []byte, ids ...string) ([]byte, error) {
+	if _, ok := c.key.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", c.key.Public())
+	}
+	root, err := parseXMLNode(envelopeXML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse envelope: %w", err)
+	}
+	signedInfo := dsSignedInfo{
+		XMLNS:                  DSigNamespace,
+		CanonicalizationMethod: dsAlgorithm{Algorithm: excC14NAlgorithm},
+		SignatureMethod:        dsAlgorithm{Algorithm: rsaSHA256Algorithm},
+	}
+	for _, id := range ids {
+		node, err := root.findByID(id)
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256(node.canonicalize())
+		signedInfo.References = append(signedInfo.References, dsReference{
+			URI:          "#" + id,
+			Transforms:   []dsAlgorithm{{Algorithm: excC14NAlgorithm}},
+			DigestMethod: dsAlgorithm{Algorithm: sha256Algorithm},
+			DigestValue:  base64.StdEncoding.EncodeToString(digest[:]),
+		})
+	}
+	signedInfoXML, err := xml.Marshal(signedInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signed info: %w", err)
+	}
+	signedInfoNode, err := parseXMLNode(signedInfoXML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signed info: %w", err)
+	}
+	// The canonical form is embedded as is, so the signed bytes are exactly
+	// what a verifier reconstructs.
+	canonicalSignedInfo := signedInfoNode.canonicalize()
+	digest := sha256.Sum256(canonicalSignedInfo)
+	signatureValue, err := c.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	var keyInfo dsKeyInfo
+	keyInfo.Reference.URI = "#" + binarySecurityTokenID
+	keyInfo.Reference.ValueType = x509TokenProfile
+	keyInfoXML, err := xml.Marshal(keyInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key info: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<ds:Signature xmlns:ds="` + DSigNamespace + `" Id="SIG-1">`)
+	buf.Write(canonicalSignedInfo)
+	buf.WriteString("<ds:SignatureValue>" + base64.StdEncoding.EncodeToString(signatureValue) + "</ds:SignatureValue>")
+	buf.Write(keyInfoXML)
+	buf.WriteString("</ds:Signature>")
+	return buf.Bytes(), nil
+}
+
+// markBody assigns the wsu:Id used to reference the Body from the signature.
+func markBody(env *Envelope) {
+	env.Body.Attrs = append(
+		slices.Clone(env.Body.Attrs),
+		xml.Attr{Name: xml.Name{Local: "xmlns:wsu"}, Value: WSUNamespace},
+		xml.Attr{Name: xml.Name{Local: "wsu:Id"}, Value: bodyID},
+	)
+}
+
+// verifyEnvelopeSignature verifies the XML signature in the wsse:Security header
+// of a serialized envelope.
+func verifyEnvelopeSignature(envelopeXML []byte, roots *x509.CertPool, now time.Time) error {
+	root, err := parseXMLNode(envelopeXML)
+	if err != nil {
+		return &SignatureError{Reason: "failed to parse envelope", Err: err}
+	}
+	var security, body *xmlNode
+	for _, c := range root.children {
+		node, ok := c.(*xmlNode)
+		if !ok {
+			continue
+		}
+		switch node.name.Local {
+		case "Header":
+			security = node.child(WSSENamespace, "Security")
+		case "Body":
+			body = node
+		}
+	}
+	if security == nil || security.child(DSigNamespace, "Signature") == nil {
+		return &SignatureError{Reason: "response is not signed"}
+	}
+	if body == nil {
+		return &SignatureError{Reason: "response has no body"}
+	}
+	timestamp := security.child(WSUNamespace, "Timestamp")
+	signature := security.child(DSigNamespace, "Signature")
+	signedInfo := signature.child(DSigNamespace, "SignedInfo")
+	if signedInfo == nil {
+		return &SignatureError{Reason: "signature has no SignedInfo"}
+	}
+	if method := algorithmOf(signedInfo, "CanonicalizationMethod"); method != excC14NAlgorithm {
+		return &SignatureError{Reason: fmt.Sprintf("unsupported canonicalization method %q", method)}
+	}
+	// Verify the references, requiring the Body and Timestamp to be covered
+	bodySigned, timestampSigned := false, false
+	for _, reference := range signedInfo.elements(DSigNamespace, "Reference") {
+		uri, _ := reference.attr("", "URI")
+		if !strings.HasPrefix(uri, "#") {
+			return &SignatureError{Reason: fmt.Sprintf("unsupported reference URI %q", uri)}
+		}
+		target, err := root.findByID(strings.TrimPrefix(uri, "#"))
+		if err != nil {
+			return &SignatureError{Reason: "invalid reference", Err: err}
+		}
+		var inclusive []string
+		if transforms := reference.child(DSigNamespace, "Transforms"); transforms != nil {
+			for _, transform := range transforms.elements(DSigNamespace, "Transform") {
+				if algorithm, _ := transform.attr("", "Algorithm"); algorithm != excC14NAlgorithm {
+					return &SignatureError{Reason: fmt.Sprintf("unsupported transform %q", algorithm)}
+				}
+				if prefixes := transform.child(excC14NAlgorithm, "InclusiveNamespaces"); prefixes != nil {
+					prefixList, _ := prefixes.attr("", "PrefixList")
+					inclusive = append(inclusive, strings.Fields(prefixList)...)
+				}
+			}
+		}
+		h, err := digestHash(algorithmOf(reference, "DigestMethod"))
+		if err != nil {
+			return &SignatureError{Reason: "invalid reference", Err: err}
+		}
+		h.Write(target.canonicalize(inclusive...))
+		expected, err := base64.StdEncoding.DecodeString(childText(reference, "DigestValue"))
+		if err != nil || !bytes.Equal(h.Sum(nil), expected) {
+			return &SignatureError{Reason: fmt.Sprintf("digest mismatch for reference %q", uri)}
+		}
+		switch target {
+		case body:
+			bodySigned = true
+		case timestamp:
+			timestampSigned = true
+		}
+	}
+	if !bodySigned {
+		return &SignatureError{Reason: "body is not covered by the signature"}
+	}
+	if timestamp != nil && !timestampSigned {
+		return &SignatureError{Reason: "timestamp is not covered by the signature"}
+	}
+	certificate, err := signatureCertificate(root, signature)
+	if err != nil {
+		return &SignatureError{Reason: "invalid signing certificate", Err: err}
+	}
+	if _, err := certificate.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return &SignatureError{Reason: "untrusted signing certificate", Err: err}
+	}
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return &SignatureError{Reason: fmt.Sprintf("unsupported public key type %T", certificate.PublicKey)}
+	}
+	var hashType crypto.Hash
+	switch method := algorithmOf(signedInfo, "SignatureMethod"); method {
+	case rsaSHA256Algorithm:
+		hashType = crypto.SHA256
+	case rsaSHA1Algorithm:
+		hashType = crypto.SHA1
+	default:
+		return &SignatureError{Reason: fmt.Sprintf("unsupported signature method %q", method)}
+	}
+	signatureValue, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(childText(signature, "SignatureValue")), ""))
+	if err != nil {
+		return &SignatureError{Reason: "invalid signature value", Err: err}
+	}
+	h := hashType.New()
+	h.Write(signedInfo.canonicalize())
+	if err := rsa.VerifyPKCS1v15(publicKey, hashType, h.Sum(nil), signatureValue); err != nil {
+		return &SignatureError{Reason: "signature mismatch", Err: err}
+	}
+	if timestamp != nil {
+		return verifyTimestamp(timestamp, now)
+	}
+	return nil
+}
+
+// verifyTimestamp checks that a signed wsu:Timestamp is current, so that
+// captured responses cannot be replayed once expired.
+func verifyTimestamp(timestamp *xmlNode, now time.Time) error {
+	parse := func(local string) (time.Time, bool, error) {
+		child := timestamp.child(WSUNamespace, local)
+		if child == nil {
+			return time.Time{}, false, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(child.text()))
+		if err != nil {
+			return time.Time{}, true, &SignatureError{Reason: "invalid timestamp " + local, Err: err}
+		}
+		return t, true, nil
+	}
+	created, ok, err := parse("Created")
+	if err != nil {
+		return err
+	}
+	if ok && created.After(now.Add(timestampClockSkew)) {
+		return &SignatureError{Reason: fmt.Sprintf("timestamp created in the future, at %s", created.Format(time.RFC3339))}
+	}
+	expires, ok, err := parse("Expires")
+	if err != nil {
+		return err
+	}
+	if ok && !expires.After(now.Add(-timestampClockSkew)) {
+		return &SignatureError{Reason: fmt.Sprintf("timestamp expired at %s", expires.Format(time.RFC3339))}
+	}
+	return nil
+}
+
+// signatureCertificate resolves the certificate referenced by the signature's
+// KeyInfo, either through a wsse:SecurityTokenReference to a binary security
+// token or an inline ds:X509Data certificate.
+func signatureCertificate(root, signature *xmlNode) (*x509.Certificate, error) {
+	keyInfo := signature.child(DSigNamespace, "KeyInfo")
+	if keyInfo == nil {
+		return nil, fmt.Errorf("signature has no KeyInfo")
+	}
+	var encoded string
+	if tokenReference := keyInfo.child(WSSENamespace, "SecurityTokenReference"); tokenReference != nil {
+		reference := tokenReference.child(WSSENamespace, "Reference")
+		if reference == nil {
+			return nil, fmt.Errorf("unsupported security token reference")
+		}
+		uri, _ := reference.attr("", "URI")
+		token, err := root.findByID(strings.TrimPrefix(uri, "#"))
+		if err != nil {
+			return nil, err
+		}
+		if token.name != (xml.Name{Space: WSSENamespace, Local: "BinarySecurityToken"}) {
+			return nil, fmt.Errorf("reference %q is not a binary security token", uri)
+		}
+		encoded = token.text()
+	} else if data := keyInfo.child(DSigNamespace, "X509Data"); data != nil {
+		encoded = childText(data, "X509Certificate")
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("no certificate found in KeyInfo")
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate encoding: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// algorithmOf returns the Algorithm attribute of the named ds child element.
+func algorithmOf(node *xmlNode, local string) string {
+	child := node.child(DSigNamespace, local)
+	if child == nil {
+		return ""
+	}
+	algorithm, _ := child.attr("", "Algorithm")
+	return algorithm
+}
+
+// childText returns the text of the named ds child element.
+func childText(node *xmlNode, local string) string {
+	child := node.child(DSigNamespace, local)
+	if child == nil {
+		return ""
+	}
+	return child.text()
+}
+
+// digestHash returns a hash for a digest method URI.
+func digestHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case sha256Algorithm:
+		return sha256.New(), nil
+	case sha1Algorithm:
+		return sha1.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported digest method %q", algorithm)
+	}
+}
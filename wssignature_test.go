@@ -0,0 +1,222 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSigningCertificate creates a self-signed RSA certificate and a pool trusting it.
+func testSigningCertificate(t *testing.T, name string) (*x509.Certificate, *rsa.PrivateKey, *x509.CertPool) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+	return certificate, key, pool
+}
+
+// signedResponse builds a signed response envelope with the given body.
+func signedResponse(t *testing.T, body string, certificate *x509.Certificate, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	return signedResponseAt(t, body, certificate, key, time.Now())
+}
+
+// signedResponseAt builds a signed response envelope with the given body and
+// a timestamp created at the given time.
+func signedResponseAt(t *testing.T, body string, certificate *x509.Certificate, key *rsa.PrivateKey, created time.Time) []byte {
+	t.Helper()
+	env, err := NewEnvelope(WithBody([]byte(body)))
+	if err != nil {
+		t.Fatalf("Failed to create envelope: %v", err)
+	}
+	config := &securityConfig{
+		signing: &signingConfig{certificate: certificate, key: key},
+		now:     func() time.Time { return created },
+	}
+	if err := applySecurity(env, config); err != nil {
+		t.Fatalf("Failed to sign envelope: %v", err)
+	}
+	data, err := xml.Marshal(env)
+	if err != nil {
+		t.Fatalf("Failed to marshal envelope: %v", err)
+	}
+	return data
+}
+
+func TestClient_Signing(t *testing.T) {
+	t.Parallel()
+	clientCert, clientKey, clientPool := testSigningCertificate(t, "client")
+	serverCert, serverKey, serverPool := testSigningCertificate(t, "server")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := verifyEnvelopeSignature(body, clientPool, time.Now()); err != nil {
+			t.Errorf("Failed to verify request signature: %v\n%s", err, body)
+		}
+		for _, expected := range []string{"wsse:BinarySecurityToken", "wsu:Timestamp", `URI="#id-Body"`, `URI="#TS-1"`} {
+			if !bytes.Contains(body, []byte(expected)) {
+				t.Errorf("Expected request to contain %s", expected)
+			}
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write(signedResponse(t, `<response>OK</response>`, serverCert, serverKey))
+	}))
+	defer server.Close()
+	client, err := NewClient(
+		WithEndpoint(server.URL),
+		WithMaxRetries(0),
+		WithSigning(clientCert, clientKey),
+		WithSignatureVerification(serverPool),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	reqEnv, _ := NewEnvelope(WithBody([]byte(`<request xmlns="urn:test">Pay &amp; settle</request>`)))
+	respEnv, err := client.Call(context.Background(), "urn:Pay", reqEnv)
+	if err != nil {
+		t.Fatalf("Client.Call() error = %v", err)
+	}
+	if string(respEnv.Body.Content) != `<response>OK</response>` {
+		t.Errorf("Unexpected response body: %s", respEnv.Body.Content)
+	}
+}
+
+func TestClient_SignatureVerificationFailure(t *testing.T) {
+	t.Parallel()
+	serverCert, serverKey, serverPool := testSigningCertificate(t, "server")
+	_, _, otherPool := testSigningCertificate(t, "other")
+	tests := []struct {
+		name       string
+		response   func() []byte
+		roots      *x509.CertPool
+		wantReason string
+	}{
+		{
+			name: "unsigned response",
+			response: func() []byte {
+				env, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+				data, _ := xml.Marshal(env)
+				return data
+			},
+			roots: serverPool,
+		},
+		{
+			name: "tampered body",
+			response: func() []byte {
+				data := signedResponse(t, `<response>OK</response>`, serverCert, serverKey)
+				return bytes.Replace(data, []byte("OK"), []byte("KO"), 1)
+			},
+			roots: serverPool,
+		},
+		{
+			name: "untrusted certificate",
+			response: func() []byte {
+				return signedResponse(t, `<response>OK</response>`, serverCert, serverKey)
+			},
+			roots: otherPool,
+		},
+		{
+			name: "expired timestamp",
+			response: func() []byte {
+				return signedResponseAt(t, `<response>OK</response>`, serverCert, serverKey, time.Now().Add(-time.Hour))
+			},
+			roots:      serverPool,
+			wantReason: "timestamp expired",
+		},
+		{
+			name: "future timestamp",
+			response: func() []byte {
+				return signedResponseAt(t, `<response>OK</response>`, serverCert, serverKey, time.Now().Add(time.Hour))
+			},
+			roots:      serverPool,
+			wantReason: "timestamp created in the future",
+		},
+		{
+			name: "unsigned timestamp",
+			response: func() []byte {
+				// Only the Body is referenced by the signature
+				env, _ := NewEnvelope(WithBody([]byte(`<response>OK</response>`)))
+				signing := &signingConfig{certificate: serverCert, key: serverKey}
+				markBody(env)
+				entry, err := securityHeaderEntry(env, &wsseSecurityContent{
+					Timestamp: &wsuTimestamp{
+						ID:      timestampID,
+						Created: time.Now().UTC().Format(securityTimeFormat),
+						Expires: time.Now().UTC().Add(time.Minute).Format(securityTimeFormat),
+					},
+					BinarySecurityToken: signing.binarySecurityToken(),
+				})
+				if err != nil {
+					t.Fatalf("Failed to build security header: %v", err)
+				}
+				env.addHeaderEntry(entry)
+				data, _ := xml.Marshal(env)
+				signature, err := signing.sign(data, bodyID)
+				if err != nil {
+					t.Fatalf("Failed to sign envelope: %v", err)
+				}
+				security := &env.Header.Entries[len(env.Header.Entries)-1]
+				security.Content = append(security.Content, signature...)
+				data, _ = xml.Marshal(env)
+				return data
+			},
+			roots:      serverPool,
+			wantReason: "timestamp is not covered",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response := tt.response()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+				_, _ = w.Write(response)
+			}))
+			defer server.Close()
+			client, err := NewClient(WithEndpoint(server.URL), WithMaxRetries(0), WithSignatureVerification(tt.roots))
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			reqEnv, _ := NewEnvelope(WithBody([]byte(`<request/>`)))
+			_, err = client.Call(context.Background(), "", reqEnv)
+			var signatureErr *SignatureError
+			if !errors.As(err, &signatureErr) {
+				t.Fatalf("Expected *SignatureError, got: %v", err)
+			}
+			if !strings.Contains(signatureErr.Reason, tt.wantReason) {
+				t.Errorf("Expected reason %q, got: %q", tt.wantReason, signatureErr.Reason)
+			}
+		})
+	}
+}